@@ -7,18 +7,28 @@ import (
 	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/jtbonhomme/asteboids/internal/config"
 	"github.com/jtbonhomme/asteboids/internal/game"
+	"github.com/jtbonhomme/asteboids/internal/metrics"
 	"github.com/jtbonhomme/asteboids/internal/sounds"
+	"github.com/jtbonhomme/asteboids/internal/window"
 	"github.com/sirupsen/logrus"
 )
 
 func Run(log *logrus.Logger, conf *config.Config) error {
 	os.Setenv("EBITEN_SCREENSHOT_KEY", "s")
-	g := game.New(log, conf)
+	g := game.New(log, conf, window.Renderer{}, window.Keyboard{})
 	log.Infof("Game: %s", g)
+	if conf.Metrics.File != "" {
+		exporter, err := metrics.Create(conf.Metrics.File, conf.Metrics.Format)
+		if err != nil {
+			return err
+		}
+		defer exporter.Close()
+		g.ExportMetrics(exporter)
+	}
 	ebiten.SetWindowSize(int(conf.ScreenWidth), int(conf.ScreenHeight))
 	ebiten.SetWindowTitle("Asteboids")
 
-	sounds.Init()
+	sounds.Init(window.NewAudio())
 	if conf.Mute {
 		sounds.Mute()
 	}
@@ -33,8 +43,8 @@ func Run(log *logrus.Logger, conf *config.Config) error {
 		ebiten.SetMaxTPS(conf.MaxTPS)
 	}
 
-	// Call ebiten.RunGame to start your game loop.
-	err := ebiten.RunGame(g)
+	// Call window.Run to start your game loop.
+	err := window.Run(g)
 	if err != nil {
 		return err
 	}
@@ -45,11 +55,9 @@ func playSoundTrack() {
 	go func() {
 		for {
 			time.Sleep(1 * time.Second)
-			_ = sounds.Beat1Player.Rewind()
-			sounds.Beat1Player.Play()
+			sounds.Play(sounds.Beat1)
 			time.Sleep(1 * time.Second)
-			_ = sounds.Beat2Player.Rewind()
-			sounds.Beat2Player.Play()
+			sounds.Play(sounds.Beat2)
 		}
 	}()
 }
@@ -5,8 +5,9 @@ import (
 	"runtime/pprof"
 
 	"github.com/dimiro1/banner"
-	"github.com/jtbonhomme/asteboids"
 	"github.com/jtbonhomme/asteboids/internal/config"
+	"github.com/jtbonhomme/asteboids/internal/evolve"
+	"github.com/jtbonhomme/asteboids/internal/sim"
 	"github.com/jtbonhomme/asteboids/internal/version"
 	"github.com/mattn/go-colorable"
 	"github.com/sirupsen/logrus"
@@ -46,7 +47,17 @@ Asteboids Version: ` + version.Read().Tag + `
 	}
 
 	log.Infof("config: %#v", conf)
-	err := asteboids.Run(log, conf)
+	var err error
+	switch conf.Command {
+	case config.SimCommand:
+		err = sim.Run(log, conf)
+	case config.EvolveCommand:
+		err = evolve.Run(log, conf)
+	case config.TrainCommand:
+		err = evolve.Train(log, conf)
+	default:
+		err = play(log, conf)
+	}
 	if err != nil {
 		log.Panic("error while running asteboids: ", err)
 	}
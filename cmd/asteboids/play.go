@@ -0,0 +1,15 @@
+//go:build !headless
+// +build !headless
+
+package main
+
+import (
+	"github.com/jtbonhomme/asteboids"
+	"github.com/jtbonhomme/asteboids/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// play runs the game in a window.
+func play(log *logrus.Logger, conf *config.Config) error {
+	return asteboids.Run(log, conf)
+}
@@ -0,0 +1,16 @@
+//go:build headless
+// +build headless
+
+package main
+
+import (
+	"errors"
+
+	"github.com/jtbonhomme/asteboids/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// play is not available when asteboids is built without display support.
+func play(log *logrus.Logger, conf *config.Config) error {
+	return errors.New("asteboids was built with the headless tag, only the sim command is available")
+}
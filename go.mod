@@ -8,8 +8,9 @@ require (
 	github.com/golang/freetype v0.0.0-20170609003504-e2365dfdc4a0
 	github.com/google/uuid v1.2.0
 	github.com/hajimehoshi/ebiten/v2 v2.0.8
-	github.com/jtbonhomme/conf v1.2.1-0.20210424133231-76044ac9b9d9 // indirect
+	github.com/jtbonhomme/conf v1.2.1-0.20210424133231-76044ac9b9d9
 	github.com/mattn/go-colorable v0.1.8
+	github.com/segmentio/objconv v1.0.1
 	github.com/sirupsen/logrus v1.8.1
 	golang.org/x/exp v0.0.0-20210405174845-4513512abef3 // indirect
 	golang.org/x/image v0.0.0-20210220032944-ac19c3e999fb
@@ -4,99 +4,242 @@ import (
 	"math"
 	"math/rand"
 
-	// anonymous import for png decoder
-	_ "image/png"
-
-	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jtbonhomme/asteboids/internal/clock"
 	"github.com/jtbonhomme/asteboids/internal/physics"
-	"github.com/jtbonhomme/asteboids/internal/sounds"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/jtbonhomme/asteboids/internal/topology"
 	"github.com/jtbonhomme/asteboids/internal/vector"
 	"github.com/sirupsen/logrus"
 )
 
+// Tier is the size of an asteroid: large asteroids split into medium ones,
+// and medium ones into small ones (rubble), which disappear when hit.
+type Tier int
+
+// Asteroids size tiers, from the largest to the smallest.
 const (
-	rubbleSplit           int     = 3
-	asteroidMaxVelocity   float64 = 0.8
-	asteroidRotationSpeed float64 = 0.02
+	LargeTier Tier = iota
+	MediumTier
+	SmallTier
 )
 
+// splitMargin is the gap between the split asteroids spawned around the exploded one, so that they do not overlap.
+const splitMargin float64 = 4
+
+// tier describes the asteroids of a size tier.
+// The speed range caps asteroids velocity, even after a bounce off a heavier asteroid.
+type tier struct {
+	size     int     // sprite width and height, in pixels
+	radius   float64 // collision radius, in pixels
+	minSpeed float64 // speed range, in pixels per 1/60 second
+	maxSpeed float64
+	spin     float64 // rotation speed
+}
+
+var tiers = [...]tier{
+	LargeTier:  {size: 100, radius: 42, minSpeed: 0.6, maxSpeed: 0.8, spin: 0.02},
+	MediumTier: {size: 70, radius: 29, minSpeed: 0.8, maxSpeed: 1.4, spin: 0.04},
+	SmallTier:  {size: 50, radius: 21, minSpeed: 1.2, maxSpeed: 2.0, spin: 0.07},
+}
+
+// clamp returns the velocity, with its speed brought within the tier speed range.
+func (t tier) clamp(velocity vector.Vector2D) vector.Vector2D {
+	speed := math.Sqrt(velocity.MagnitudeSquared())
+	switch {
+	case speed > t.maxSpeed:
+		velocity.SetMagnitude(t.maxSpeed)
+	case speed < t.minSpeed && speed > 0:
+		velocity.SetMagnitude(t.minSpeed)
+	}
+	return velocity
+}
+
+// Splitting configures how asteroids split when they explode.
+// It is shared by all asteroids of a game.
+type Splitting struct {
+	Count  int                           // number of smaller asteroids an asteroid splits into, 0 to never split
+	Spread float64                       // speed at which split asteroids move apart
+	Images [SmallTier + 1][]render.Image // sprites of each tier
+}
+
+// image returns a random sprite of a tier.
+func (s *Splitting) image(rng *rand.Rand, t Tier) render.Image {
+	if len(s.Images[t]) == 0 {
+		return nil
+	}
+	return s.Images[t][rng.Intn(len(s.Images[t]))]
+}
+
+// tierMass returns the mass of an asteroid of a tier: split asteroids share the mass
+// of the exploded one, so that they keep its momentum.
+func tierMass(t Tier, splitting *Splitting) float64 {
+	mass := rubbleMass
+	if splitting == nil || splitting.Count <= 1 {
+		return mass
+	}
+	for i := t; i < SmallTier; i++ {
+		mass *= float64(splitting.Count)
+	}
+	return mass
+}
+
 // Asteroid is a PhysicalBody agent
-// It represents a bullet shot by a starship agent.
+// It represents a large or medium asteroid, which splits into smaller ones when it explodes.
 type Asteroid struct {
 	physics.Body
-	rubbleImages []*ebiten.Image
+	tier      Tier
+	splitting *Splitting
 }
 
-// NewAsteroid creates a new Asteroid (PhysicalBody agent)
+// NewAsteroid creates a new large Asteroid (PhysicalBody agent)
+// It heads in a random direction, at a random speed of the large tier range.
 func NewAsteroid(
 	log *logrus.Logger,
+	rng *rand.Rand,
+	clk *clock.Clock,
+	topo topology.Topology,
 	x, y,
 	screenWidth, screenHeight float64,
 	cbr physics.AgentRegister,
 	cbu physics.AgentUnregister,
-	asteroidImage *ebiten.Image,
-	rubbleImages []*ebiten.Image,
+	asteroidImage render.Image,
+	splitting *Splitting,
+	debug bool) *Asteroid {
+	orientation := math.Pi / 16 * float64(rng.Intn(32))
+	speed := tiers[LargeTier].minSpeed + rng.Float64()*(tiers[LargeTier].maxSpeed-tiers[LargeTier].minSpeed)
+	velocity := vector.Vector2D{
+		X: speed * math.Cos(orientation),
+		Y: speed * math.Sin(orientation),
+	}
+	return newAsteroid(log, rng, clk, topo, LargeTier, x, y, velocity, screenWidth, screenHeight, cbr, cbu, asteroidImage, splitting, debug)
+}
+
+// newAsteroid creates a new Asteroid of a tier, moving at a given velocity.
+func newAsteroid(
+	log *logrus.Logger,
+	rng *rand.Rand,
+	clk *clock.Clock,
+	topo topology.Topology,
+	t Tier,
+	x, y float64,
+	velocity vector.Vector2D,
+	screenWidth, screenHeight float64,
+	cbr physics.AgentRegister,
+	cbu physics.AgentUnregister,
+	asteroidImage render.Image,
+	splitting *Splitting,
 	debug bool) *Asteroid {
-	a := Asteroid{}
+	a := Asteroid{
+		tier:      t,
+		splitting: splitting,
+	}
+	a.Rand = rng
+	a.Clock = clk
+	a.Topology = topo
 	a.AgentType = physics.AsteroidAgent
 	a.Register = cbr
 	a.Unregister = cbu
 
-	a.Orientation = math.Pi / 16 * float64(rand.Intn(32))
+	a.Orientation = velocity.Theta()
 
-	a.Init(vector.Vector2D{
-		X: asteroidMaxVelocity * math.Cos(a.Orientation),
-		Y: asteroidMaxVelocity * math.Sin(a.Orientation),
-	})
+	a.Init(velocity)
 	a.Log = log
-	a.LimitVelocity(asteroidMaxVelocity)
+	a.LimitVelocity(tiers[t].maxSpeed)
+	a.SetMass(tierMass(t, splitting))
+	a.Spin(tiers[t].spin)
 
 	a.Move(vector.Vector2D{
 		X: x,
 		Y: y,
 	})
-	a.PhysicWidth = 100
-	a.PhysicHeight = 100
+	a.PhysicWidth = float64(tiers[t].size)
+	a.PhysicHeight = float64(tiers[t].size)
+	a.Shape = physics.Circle{R: tiers[t].radius}
 	a.ScreenWidth = screenWidth
 	a.ScreenHeight = screenHeight
 
 	a.Image = asteroidImage
-	a.rubbleImages = rubbleImages
 	a.Debug = debug
 	return &a
 }
 
+// Tier returns the size tier of the asteroid.
+func (a *Asteroid) Tier() Tier {
+	return a.tier
+}
+
+// SetSpeed changes the asteroid speed, keeping its heading.
+func (a *Asteroid) SetSpeed(speed float64) {
+	velocity := a.Velocity()
+	impulse := velocity
+	impulse.SetMagnitude(speed)
+	impulse.Subtract(velocity)
+	impulse.Multiply(a.Mass())
+	a.LimitVelocity(speed)
+	a.ApplyImpulse(impulse)
+}
+
 // Update proceeds the game state.
-// Update is called every tick (1/60 [s] by default).
-func (a *Asteroid) Update() {
-	defer a.Body.Update()
-	a.Rotate(asteroidRotationSpeed)
+// Update is called every physics step, dt seconds long (1/60 [s] by default).
+func (a *Asteroid) Update(dt float64) {
+	a.Body.Update(dt)
 }
 
 // Draw draws the game screen.
 // Draw is called every frame (typically 1/60[s] for 60Hz display).
-func (a *Asteroid) Draw(screen *ebiten.Image) {
+func (a *Asteroid) Draw(screen render.Screen) {
 	a.Body.Draw(screen)
 }
 
 // Explode proceeds the asteroid explosion and termination.
-// For the first explosion, it splits into smaller asteroids (e.g. rubble).
-// For the second explosion, each rubble disapear from game.
+// A large asteroid splits into medium ones, and a medium one into small ones (rubble).
+// Split asteroids move apart evenly, at a speed within the range of their tier.
 func (a *Asteroid) Explode() {
 	defer a.Unregister(a.ID(), a.Type())
-	go func() {
-		_ = sounds.BangMediumPlayer.Rewind()
-		sounds.BangMediumPlayer.Play()
-	}()
-
-	for i := 0; i < rubbleSplit; i++ {
-		rubble := NewRubble(a.Log,
-			a.Position().X,
-			a.Position().Y,
+	if a.splitting == nil || a.splitting.Count <= 0 {
+		return
+	}
+
+	child := a.tier + 1
+	count := a.splitting.Count
+	offset := tiers[child].radius + splitMargin
+	theta := 2 * math.Pi * a.Rand.Float64()
+	for i := 0; i < count; i++ {
+		direction := vector.Vector2D{
+			X: math.Cos(theta + 2*math.Pi*float64(i)/float64(count)),
+			Y: math.Sin(theta + 2*math.Pi*float64(i)/float64(count)),
+		}
+		velocity := direction
+		velocity.Multiply(a.splitting.Spread)
+		velocity.Add(a.Velocity())
+		velocity = tiers[child].clamp(velocity)
+		x := a.Position().X + direction.X*offset
+		y := a.Position().Y + direction.Y*offset
+		image := a.splitting.image(a.Rand, child)
+		if child == SmallTier {
+			a.Register(NewRubble(a.Log,
+				a.Rand,
+				a.Clock,
+				a.Topology,
+				x, y,
+				velocity,
+				a.ScreenWidth, a.ScreenHeight,
+				a.Unregister,
+				image,
+				a.Debug))
+			continue
+		}
+		a.Register(newAsteroid(a.Log,
+			a.Rand,
+			a.Clock,
+			a.Topology,
+			child,
+			x, y,
+			velocity,
 			a.ScreenWidth, a.ScreenHeight,
-			a.Unregister,
-			a.rubbleImages[rand.Intn(5)],
-			a.Debug)
-		a.Register(rubble)
+			a.Register, a.Unregister,
+			image,
+			a.splitting,
+			a.Debug))
 	}
 }
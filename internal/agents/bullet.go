@@ -2,38 +2,44 @@ package agents
 
 import (
 	"math"
+	"math/rand"
+	"time"
 
-	// anonymous import for png decoder
-	_ "image/png"
-
-	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jtbonhomme/asteboids/internal/clock"
 	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/jtbonhomme/asteboids/internal/topology"
 	"github.com/jtbonhomme/asteboids/internal/vector"
 	"github.com/sirupsen/logrus"
 )
 
 const (
-	bulletVelocity float64 = 20.0
-	bulletTTL      int     = 30
+	bulletVelocity float64       = 20.0
+	bulletTTL      time.Duration = 500 * time.Millisecond
+	bulletMass     float64       = 0.1
 )
 
 // Bullet is a PhysicalBody agent
 // It represents a bullet shot by a starship agent.
 type Bullet struct {
 	physics.Body
-	lifespan int
+	birthTime time.Duration
 }
 
 // NewBullet creates a new Bullet (PhysicalBody agent)
 func NewBullet(log *logrus.Logger,
+	rng *rand.Rand,
+	clk *clock.Clock,
+	topo topology.Topology,
 	x, y float64,
 	orientation float64,
 	screenWidth, screenHeight float64,
 	cb physics.AgentUnregister,
-	bulletImage *ebiten.Image) *Bullet {
-	b := Bullet{
-		lifespan: bulletTTL,
-	}
+	bulletImage render.Image) *Bullet {
+	b := Bullet{}
+	b.Rand = rng
+	b.Clock = clk
+	b.Topology = topo
 	b.AgentType = physics.BulletAgent
 	b.Unregister = cb
 
@@ -43,6 +49,9 @@ func NewBullet(log *logrus.Logger,
 		X: bulletVelocity * math.Cos(b.Orientation),
 		Y: bulletVelocity * math.Sin(b.Orientation),
 	})
+	b.LimitVelocity(bulletVelocity)
+	b.SetMass(bulletMass)
+	b.birthTime = b.Clock.Now()
 	b.Log = log
 
 	b.Move(vector.Vector2D{
@@ -51,6 +60,7 @@ func NewBullet(log *logrus.Logger,
 	})
 	b.PhysicWidth = 16
 	b.PhysicHeight = 16
+	b.Shape = physics.Box{W: 12, H: 4}
 	b.ScreenWidth = screenWidth
 	b.ScreenHeight = screenHeight
 	b.Image = bulletImage
@@ -58,19 +68,18 @@ func NewBullet(log *logrus.Logger,
 }
 
 // Update proceeds the game state.
-// Update is called every tick (1/60 [s] by default).
-// Update maintains a TTL counter to limit live of bullets.
-func (b *Bullet) Update() {
-	defer b.Body.UpdatePosition()
-	b.lifespan--
-	if b.lifespan == 0 {
+// Update is called every physics step, dt seconds long (1/60 [s] by default).
+// Update checks the bullet age to limit live of bullets.
+func (b *Bullet) Update(dt float64) {
+	defer b.Body.Update(dt)
+	if b.Clock.Since(b.birthTime) >= bulletTTL {
 		b.SelfDestroy()
 	}
 }
 
 // Draw draws the game screen.
 // Draw is called every frame (typically 1/60[s] for 60Hz display).
-func (b *Bullet) Draw(screen *ebiten.Image) {
+func (b *Bullet) Draw(screen render.Screen) {
 	b.Body.Draw(screen)
 }
 
@@ -1,83 +1,84 @@
 package agents
 
 import (
-	"math"
 	"math/rand"
 
-	// anonymous import for png decoder
-	_ "image/png"
-
-	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jtbonhomme/asteboids/internal/clock"
 	"github.com/jtbonhomme/asteboids/internal/physics"
-	"github.com/jtbonhomme/asteboids/internal/sounds"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/jtbonhomme/asteboids/internal/topology"
 	"github.com/jtbonhomme/asteboids/internal/vector"
 	"github.com/sirupsen/logrus"
 )
 
-const (
-	rubbleMaxVelocity   float64 = 2.0
-	rubbleRotationSpeed float64 = 0.07
-)
+// rubbleMass is the mass of the smallest asteroids.
+const rubbleMass float64 = 1
 
 // Rubble is a PhysicalBody agent
-// It represents a bullet shot by a starship agent.
+// It represents a small asteroid, the last tier of splitting, which disappears when hit.
 type Rubble struct {
 	physics.Body
 }
 
 // NewRubble creates a new Rubble (PhysicalBody agent)
 func NewRubble(log *logrus.Logger,
-	x, y,
+	rng *rand.Rand,
+	clk *clock.Clock,
+	topo topology.Topology,
+	x, y float64,
+	velocity vector.Vector2D,
 	screenWidth, screenHeight float64,
 	cbu physics.AgentUnregister,
-	rubbleImage *ebiten.Image,
+	rubbleImage render.Image,
 	debug bool) *Rubble {
 	r := Rubble{}
+	r.Rand = rng
+	r.Clock = clk
+	r.Topology = topo
 	r.AgentType = physics.RubbleAgent
 	r.Unregister = cbu
 
-	r.Orientation = math.Pi / 16 * float64(rand.Intn(32))
+	r.Orientation = velocity.Theta()
 
-	r.Init(vector.Vector2D{
-		X: rubbleMaxVelocity * math.Cos(r.Orientation),
-		Y: rubbleMaxVelocity * math.Sin(r.Orientation),
-	})
+	r.Init(velocity)
 	r.Log = log
-	r.LimitVelocity(rubbleMaxVelocity)
+	r.LimitVelocity(tiers[SmallTier].maxSpeed)
+	r.SetMass(rubbleMass)
+	r.Spin(tiers[SmallTier].spin)
 
 	r.Move(vector.Vector2D{
 		X: x,
 		Y: y,
 	})
-	r.PhysicWidth = 50
-	r.PhysicHeight = 50
+	r.PhysicWidth = float64(tiers[SmallTier].size)
+	r.PhysicHeight = float64(tiers[SmallTier].size)
+	r.Shape = physics.Circle{R: tiers[SmallTier].radius}
 	r.ScreenWidth = screenWidth
 	r.ScreenHeight = screenHeight
 
-	/*filename := fmt.Sprintf("./resources/images/rubble%d.png", rand.Intn(5))*/
 	r.Image = rubbleImage
 	r.Debug = debug
 	return &r
 }
 
+// Tier returns the size tier of the rubble, the smallest one.
+func (r *Rubble) Tier() Tier {
+	return SmallTier
+}
+
 // Update proceeds the game state.
-// Update is called every tick (1/60 [s] by default).
-func (r *Rubble) Update() {
-	defer r.Body.UpdatePosition()
-	r.Rotate(rubbleRotationSpeed)
+// Update is called every physics step, dt seconds long (1/60 [s] by default).
+func (r *Rubble) Update(dt float64) {
+	r.Body.Update(dt)
 }
 
 // Draw draws the game screen.
 // Draw is called every frame (typically 1/60[s] for 60Hz display).
-func (r *Rubble) Draw(screen *ebiten.Image) {
+func (r *Rubble) Draw(screen render.Screen) {
 	defer r.Body.Draw(screen)
 }
 
 // Explode proceeds the rubble termination.
 func (r *Rubble) Explode() {
 	r.Unregister(r.ID(), r.Type())
-	go func() {
-		_ = sounds.BangSmallPlayer.Rewind()
-		sounds.BangSmallPlayer.Play()
-	}()
 }
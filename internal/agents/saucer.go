@@ -0,0 +1,184 @@
+package agents
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/jtbonhomme/asteboids/internal/clock"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	saucerTurnDelay      time.Duration = time.Second
+	bigSaucerSpeed       float64       = 1.5
+	smallSaucerSpeed     float64       = 2.5
+	bigSaucerFireDelay   time.Duration = 1200 * time.Millisecond
+	smallSaucerFireDelay time.Duration = 800 * time.Millisecond
+	// big saucers shoot anywhere, small ones aim at the starship within 10°
+	bigSaucerAimError   float64 = math.Pi
+	smallSaucerAimError float64 = math.Pi / 18
+)
+
+// Saucer is a PhysicalBody agent.
+// It represents a flying saucer, which crosses the screen and shoots at the starship.
+type Saucer struct {
+	physics.Body
+	small        bool
+	speed        float64
+	direction    float64
+	travelled    float64
+	lastShotTime time.Duration
+	lastTurnTime time.Duration
+	bulletImage  render.Image
+}
+
+// NewSaucer creates a new Saucer (PhysicalBody agent)
+// The saucer enters from the left or right edge of the screen, at a random height,
+// and leaves the game once it has crossed the screen.
+func NewSaucer(
+	log *logrus.Logger,
+	rng *rand.Rand,
+	clk *clock.Clock,
+	topo topology.Topology,
+	small bool,
+	screenWidth, screenHeight float64,
+	cbr physics.AgentRegister,
+	cbu physics.AgentUnregister,
+	vision physics.AgentVision,
+	saucerImage render.Image,
+	bulletImage render.Image,
+	debug bool) *Saucer {
+	s := Saucer{
+		small:        small,
+		speed:        bigSaucerSpeed,
+		direction:    1,
+		lastShotTime: clk.Now(),
+		lastTurnTime: clk.Now(),
+		bulletImage:  bulletImage,
+	}
+	s.Rand = rng
+	s.Clock = clk
+	s.Topology = topo
+	s.AgentType = physics.SaucerAgent
+	s.Register = cbr
+	s.Unregister = cbu
+	s.Vision = vision
+	s.Log = log
+
+	s.PhysicWidth = 40
+	s.PhysicHeight = 24
+	if small {
+		s.speed = smallSaucerSpeed
+		s.PhysicWidth = 20
+		s.PhysicHeight = 12
+	}
+	s.Shape = physics.Box{W: s.PhysicWidth, H: s.PhysicHeight / 2}
+
+	x := 0.0
+	if s.Rand.Intn(2) == 0 {
+		s.direction = -1
+		x = screenWidth
+	}
+	s.Init(vector.Vector2D{
+		X: s.direction * s.speed,
+		Y: 0,
+	})
+	s.LimitVelocity(s.speed)
+	s.Move(vector.Vector2D{
+		X: x,
+		Y: s.Rand.Float64() * screenHeight,
+	})
+	s.ScreenWidth = screenWidth
+	s.ScreenHeight = screenHeight
+
+	s.Image = saucerImage
+	s.Debug = debug
+	return &s
+}
+
+// Small returns true for a small saucer.
+func (s *Saucer) Small() bool {
+	return s.small
+}
+
+// Update proceeds the game state.
+// Update is called every physics step, dt seconds long (1/60 [s] by default).
+func (s *Saucer) Update(dt float64) {
+	if s.Clock.Since(s.lastTurnTime) >= saucerTurnDelay {
+		s.lastTurnTime = s.Clock.Now()
+		s.turn()
+	}
+
+	fireDelay := bigSaucerFireDelay
+	if s.small {
+		fireDelay = smallSaucerFireDelay
+	}
+	if s.Clock.Since(s.lastShotTime) >= fireDelay {
+		s.Shot()
+	}
+
+	s.Integrate(dt)
+	s.travelled += s.speed * physics.Ticks(dt)
+	if s.travelled >= s.ScreenWidth {
+		s.SelfDestroy()
+	}
+}
+
+// turn randomly zigzags: the saucer flies straight, up or down, while crossing the screen.
+func (s *Saucer) turn() {
+	target := vector.Vector2D{
+		X: s.direction * s.speed,
+		Y: float64(s.Rand.Intn(3)-1) * s.speed / 2,
+	}
+	target.Subtract(s.Velocity())
+	target.Multiply(s.Mass())
+	s.ApplyImpulse(target)
+}
+
+// Shot adds a new saucer bullet to the game, aimed at the starship.
+func (s *Saucer) Shot() {
+	s.lastShotTime = s.Clock.Now()
+
+	aimError := bigSaucerAimError
+	if s.small {
+		aimError = smallSaucerAimError
+	}
+	orientation := 2 * math.Pi * s.Rand.Float64()
+	for _, a := range s.Vision(s.Position().X, s.Position().Y) {
+		if a.Type() != physics.StarshipAgent {
+			continue
+		}
+		delta := s.World().Delta(s.Position(), a.Position())
+		orientation = math.Atan2(delta.Y, delta.X) + aimError*(2*s.Rand.Float64()-1)
+		break
+	}
+
+	bullet := NewBullet(s.Log,
+		s.Rand,
+		s.Clock,
+		s.Topology,
+		s.Position().X, s.Position().Y,
+		orientation,
+		s.ScreenWidth,
+		s.ScreenHeight,
+		s.Unregister,
+		s.bulletImage)
+	bullet.AgentType = physics.SaucerBulletAgent
+	s.Register(bullet)
+}
+
+// Draw draws the game screen.
+// Draw is called every frame (typically 1/60[s] for 60Hz display).
+func (s *Saucer) Draw(screen render.Screen) {
+	s.Body.Draw(screen)
+}
+
+// SelfDestroy removes the agent from the game
+func (s *Saucer) SelfDestroy() {
+	s.Unregister(s.ID(), s.Type())
+}
@@ -1,13 +1,16 @@
 package agents
 
 import (
-	"time"
-
+	"image/color"
 	"math"
+	"math/rand"
+	"time"
 
-	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jtbonhomme/asteboids/internal/clock"
+	"github.com/jtbonhomme/asteboids/internal/input"
 	"github.com/jtbonhomme/asteboids/internal/physics"
-	"github.com/jtbonhomme/asteboids/internal/sounds"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/jtbonhomme/asteboids/internal/topology"
 	"github.com/jtbonhomme/asteboids/internal/vector"
 	"github.com/sirupsen/logrus"
 )
@@ -17,30 +20,63 @@ const (
 	rotationAngle        float64       = math.Pi / 36 // rotation of 5°
 	starshipMaxVelocity  float64       = 3.0
 	starshipAcceleration float64       = 0.2
+	blinkPeriod          time.Duration = 150 * time.Millisecond
+	shieldRadius         float64       = 32
+	shieldSegments       int           = 24
+	shieldMinEnergy      float64       = 0.1 // energy needed to raise a depleted shield again
 )
 
+// Abilities configures the starship defensive abilities.
+// It is shared by all starships of a game.
+type Abilities struct {
+	HyperspaceCooldown time.Duration // time between two hyperspace jumps
+	HyperspaceFailure  float64       // probability to self destroy when jumping
+	ShieldDrain        float64       // energy drained per second while the shield is up, from a full energy of 1
+	ShieldRecharge     float64       // energy recharged per second while the shield is down
+}
+
 // Starship is a PhysicalBody agent.
 // It represents a playable star ship.
 type Starship struct {
 	physics.Body
-	lastBulletTime time.Time
-	bulletImage    *ebiten.Image
+	lastBulletTime    time.Duration
+	invulnerableUntil time.Duration
+	nextJumpTime      time.Duration
+	energy            float64
+	depleted          bool
+	thrusting         bool
+	abilities         *Abilities
+	bulletImage       render.Image
+	input             input.Input
 }
 
 // NewStarship creates a new Starship (PhysicalBody agent)
+// Its hyperspace jump is ready, and its shield energy full. A nil abilities disables both.
 func NewStarship(
 	log *logrus.Logger,
+	rng *rand.Rand,
+	clk *clock.Clock,
+	topo topology.Topology,
 	x, y,
 	screenWidth, screenHeight float64,
 	cbr physics.AgentRegister,
 	cbu physics.AgentUnregister,
 	vision physics.AgentVision,
-	starshipImage *ebiten.Image,
-	bulletImage *ebiten.Image,
+	in input.Input,
+	starshipImage render.Image,
+	bulletImage render.Image,
+	abilities *Abilities,
 	debug bool) *Starship {
 	s := Starship{
-		lastBulletTime: time.Now(),
+		lastBulletTime: clk.Now(),
+		nextJumpTime:   clk.Now(),
+		energy:         1,
+		abilities:      abilities,
+		input:          in,
 	}
+	s.Rand = rng
+	s.Clock = clk
+	s.Topology = topo
 	s.AgentType = physics.StarshipAgent
 	s.Register = cbr
 	s.Unregister = cbu
@@ -61,6 +97,14 @@ func NewStarship(
 	})
 	s.PhysicWidth = 50
 	s.PhysicHeight = 50
+	// the ship sprite is a triangle pointing to the right
+	s.Shape = physics.Polygon{
+		Points: []vector.Vector2D{
+			{X: 23, Y: 0},
+			{X: -20, Y: -21},
+			{X: -20, Y: 21},
+		},
+	}
 	s.ScreenWidth = screenWidth
 	s.ScreenHeight = screenHeight
 	s.Log = log
@@ -73,54 +117,87 @@ func NewStarship(
 }
 
 // Update proceeds the game state.
-// Update is called every tick (1/60 [s] by default).
-func (s *Starship) Update() {
-	if ebiten.IsKeyPressed(ebiten.KeyLeft) {
-		s.Rotate(-rotationAngle)
-	} else if ebiten.IsKeyPressed(ebiten.KeyRight) {
-		s.Rotate(rotationAngle)
+// Update is called every physics step, dt seconds long (1/60 [s] by default).
+func (s *Starship) Update(dt float64) {
+	if s.input.IsKeyPressed(input.KeyLeft) {
+		s.Rotate(-rotationAngle * physics.Ticks(dt))
+	} else if s.input.IsKeyPressed(input.KeyRight) {
+		s.Rotate(rotationAngle * physics.Ticks(dt))
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyUp) {
+	s.thrusting = s.input.IsKeyPressed(input.KeyUp)
+	if s.thrusting {
 		acceleration := vector.Vector2D{
 			X: math.Cos(s.Orientation),
 			Y: math.Sin(s.Orientation),
 		}
 		acceleration.Multiply(starshipAcceleration)
 		s.Accelerate(acceleration)
-		go func() {
-			_ = sounds.ThrustPlayer.Rewind()
-			sounds.ThrustPlayer.Play()
-		}()
 	} else {
 		s.Accelerate(vector.Vector2D{})
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyEscape) {
+	if s.input.IsKeyPressed(input.KeyEscape) {
 		s.SelfDestroy()
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeySpace) {
+	if s.input.IsKeyPressed(input.KeySpace) {
 		s.Shot()
-		go func() {
-			_ = sounds.FirePlayer.Rewind()
-			sounds.FirePlayer.Play()
-		}()
 	}
 
-	s.UpdateVelocity()
-	s.UpdatePosition()
+	if s.abilities != nil {
+		s.updateShield(dt)
+		if s.input.IsKeyPressed(input.KeyH) {
+			s.Hyperspace()
+		}
+	}
+
+	s.Integrate(dt)
+}
+
+// updateShield drains energy while the shield is up, and recharges it while it is down.
+// Once drained, the shield is depleted until its key is released and some energy is recharged.
+func (s *Starship) updateShield(dt float64) {
+	if s.Shielded() {
+		s.energy = math.Max(s.energy-s.abilities.ShieldDrain*dt, 0)
+		s.depleted = s.energy == 0
+		return
+	}
+	s.energy = math.Min(s.energy+s.abilities.ShieldRecharge*dt, 1)
+	if s.depleted && !s.input.IsKeyPressed(input.KeyDown) && s.energy >= shieldMinEnergy {
+		s.depleted = false
+	}
+}
+
+// Hyperspace teleports the starship to a random position, once the cooldown is over.
+// The jump may fail, and the starship self destroys.
+func (s *Starship) Hyperspace() {
+	if s.Clock.Now() < s.nextJumpTime {
+		return
+	}
+	s.nextJumpTime = s.Clock.Now() + s.abilities.HyperspaceCooldown
+	if s.Rand.Float64() < s.abilities.HyperspaceFailure {
+		s.SelfDestroy()
+		return
+	}
+	s.Move(vector.Vector2D{
+		X: s.Rand.Float64() * s.ScreenWidth,
+		Y: s.Rand.Float64() * s.ScreenHeight,
+	})
 }
 
 // Shot adds a new bullet to the game.
 func (s *Starship) Shot() {
 	// throtlle call to avoid continuous shooting
-	if time.Since(s.lastBulletTime) < bulletThrottle {
+	if s.Clock.Since(s.lastBulletTime) < bulletThrottle {
 		return
 	}
-	s.lastBulletTime = time.Now()
+	s.lastBulletTime = s.Clock.Now()
 
 	bullet := NewBullet(s.Log,
+		s.Rand,
+		s.Clock,
+		s.Topology,
 		s.Position().X, s.Position().Y,
 		s.Orientation,
 		s.ScreenWidth,
@@ -132,26 +209,70 @@ func (s *Starship) Shot() {
 
 // Draw draws the game screen.
 // Draw is called every frame (typically 1/60[s] for 60Hz display).
-func (s *Starship) Draw(screen *ebiten.Image) {
-	defer s.Body.Draw(screen)
+// An invulnerable starship blinks.
+func (s *Starship) Draw(screen render.Screen) {
+	if !s.Invulnerable() || (s.Clock.Now()/blinkPeriod)%2 == 0 {
+		defer s.Body.Draw(screen)
+	}
+	if s.Shielded() {
+		s.drawShield(screen)
+	}
 	nearestAgent := s.Vision(s.Position().X, s.Position().Y)
 	s.LinkAgents(screen, nearestAgent, []string{physics.AsteroidAgent, physics.RubbleAgent})
 }
 
+// drawShield draws the shield around the starship, fading as its energy drains.
+func (s *Starship) drawShield(screen render.Screen) {
+	clr := color.Gray16{Y: uint16(0x4000 + 0xbfff*s.energy)}
+	x, y := s.Position().X, s.Position().Y
+	prevX, prevY := x+shieldRadius, y
+	for i := 1; i <= shieldSegments; i++ {
+		theta := 2 * math.Pi * float64(i) / float64(shieldSegments)
+		nextX, nextY := x+shieldRadius*math.Cos(theta), y+shieldRadius*math.Sin(theta)
+		screen.DrawLine(prevX, prevY, nextX, nextY, clr)
+		prevX, prevY = nextX, nextY
+	}
+}
+
+// SetInvulnerable prevents the starship from colliding for a given duration.
+func (s *Starship) SetInvulnerable(d time.Duration) {
+	s.invulnerableUntil = s.Clock.Now() + d
+}
+
+// Invulnerable returns true while the starship can not collide.
+func (s *Starship) Invulnerable() bool {
+	return s.Clock.Now() < s.invulnerableUntil
+}
+
+// Shielded returns true while the starship shield is up: its key is pressed and it is not depleted.
+func (s *Starship) Shielded() bool {
+	return s.abilities != nil && s.input.IsKeyPressed(input.KeyDown) && !s.depleted && s.energy > 0
+}
+
+// Thrusting returns true when the starship accelerated during its last update.
+func (s *Starship) Thrusting() bool {
+	return s.thrusting
+}
+
+// Energy returns the shield energy left, from 0 (empty) to 1 (full).
+func (s *Starship) Energy() float64 {
+	return s.energy
+}
+
+// HyperspaceCooldown returns the time left before the next hyperspace jump.
+func (s *Starship) HyperspaceCooldown() time.Duration {
+	if s.Clock.Now() >= s.nextJumpTime {
+		return 0
+	}
+	return s.nextJumpTime - s.Clock.Now()
+}
+
 // SelfDestroy removes the agent from the game
 func (s *Starship) SelfDestroy() {
-	go func() {
-		_ = sounds.BangLargePlayer.Rewind()
-		sounds.BangLargePlayer.Play()
-	}()
 	s.Unregister(s.ID(), s.Type())
 }
 
 // Explode proceeds the rubble termination.
 func (s *Starship) Explode() {
 	s.Unregister(s.ID(), s.Type())
-	go func() {
-		_ = sounds.BangLargePlayer.Rewind()
-		sounds.BangLargePlayer.Play()
-	}()
 }
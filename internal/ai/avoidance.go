@@ -0,0 +1,100 @@
+package ai
+
+import (
+	"math"
+
+	"github.com/jtbonhomme/asteboids/internal/ai/steering"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+// avoidObstacles returns the force steering the boid away from the closest asteroid or rubble
+// lying ahead, within the look-ahead distance along its heading.
+func (b *Boid) avoidObstacles(agents []physics.Physic) vector.Vector2D {
+	result := vector.Vector2D{}
+	lookAhead := b.flocking.Avoidance.Radius
+	if lookAhead <= 0 {
+		return result
+	}
+	heading := vector.Vector2D{
+		X: math.Cos(b.heading()),
+		Y: math.Sin(b.heading()),
+	}
+	ahead := heading
+	ahead.Multiply(lookAhead)
+
+	closest := math.Inf(1)
+	for _, agent := range agents {
+		if agent.Type() != physics.AsteroidAgent && agent.Type() != physics.RubbleAgent {
+			continue
+		}
+		center := b.World().Delta(b.Position(), agent.Position())
+		radius := agent.CollisionShape().BoundingRadius() + b.CollisionShape().BoundingRadius()
+		if !segmentCircleIntersect(ahead, center, radius) {
+			continue
+		}
+		// distance to the obstacle along the heading
+		t := center.X*heading.X + center.Y*heading.Y
+		if t >= closest {
+			continue
+		}
+		closest = t
+		// steer sideways, from the obstacle center to the point ahead of the boid
+		result = heading
+		result.Multiply(t)
+		result.Subtract(center)
+		if result.IsNil() {
+			// obstacle right ahead, turn left
+			result = vector.Vector2D{X: heading.Y, Y: -heading.X}
+		}
+	}
+	if closest == math.Inf(1) {
+		return result
+	}
+	result.Normalize()
+	result.Multiply(b.flocking.MaxForce)
+	return result
+}
+
+// fleeBullets returns the force steering the boid away from the bullets and predators within its threat radius.
+// Closest threats weigh more. Predators do not flee each other.
+func (b *Boid) fleeBullets(agents []physics.Physic) vector.Vector2D {
+	result := vector.Vector2D{}
+	var nBullets float64 = 0.0
+	for _, agent := range agents {
+		if agent.Type() != physics.BulletAgent && (agent.Type() != physics.PredatorAgent || b.Type() == physics.PredatorAgent) {
+			continue
+		}
+		d := b.World().Distance(b.Position(), agent.Position())
+		if d >= b.flocking.Flee.Radius || d == 0 {
+			continue
+		}
+		nBullets++
+		diff := b.World().Delta(agent.Position(), b.Position())
+		diff.Normalize()
+		diff.Divide(d)
+		result.Add(diff)
+	}
+	if nBullets > 0 {
+		result.Normalize()
+		result.Multiply(b.flocking.MaxSpeed)
+		result.Subtract(b.Velocity())
+		result.Limit(b.flocking.MaxForce)
+	}
+	return result
+}
+
+// avoidWalls returns the force steering the boid back to the center of a bounded world,
+// when it gets closer to a wall than the wall margin.
+func (b *Boid) avoidWalls() vector.Vector2D {
+	walls, ok := b.World().(topology.Bounded)
+	margin := b.flocking.Walls.Radius
+	if !ok || margin <= 0 {
+		return vector.Vector2D{}
+	}
+	return steering.Contain{
+		Max:    vector.Vector2D{X: walls.Width, Y: walls.Height},
+		Margin: margin,
+	}.Steer(b)
+}
@@ -0,0 +1,112 @@
+package ai_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/ai"
+	"github.com/jtbonhomme/asteboids/internal/clock"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+	"github.com/sirupsen/logrus"
+)
+
+func TestAvoidance(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	flocking := &ai.Flocking{
+		MaxSpeed:    3,
+		MaxForce:    0.3,
+		Avoidance:   ai.Rule{Radius: 60, Weight: 1},
+		Flee:        ai.Rule{Radius: 50, Weight: 1},
+		Walls:       ai.Rule{Radius: 50, Weight: 1},
+		FieldOfView: 360,
+	}
+
+	tests := []struct {
+		name     string
+		topo     topology.Topology
+		x, y     float64
+		velocity vector.Vector2D
+		agents   []physics.Physic
+		// check returns true if the boid velocity steers the expected way
+		check func(v vector.Vector2D) bool
+	}{
+		{
+			name:     "asteroid ahead",
+			topo:     topology.Infinite{},
+			x:        100,
+			y:        100,
+			velocity: vector.Vector2D{X: 3},
+			agents:   []physics.Physic{newAgent(rng, physics.AsteroidAgent, 140, 105, physics.Circle{R: 10})},
+			check:    func(v vector.Vector2D) bool { return v.Y < 0 },
+		},
+		{
+			name:     "bullet behind",
+			topo:     topology.Infinite{},
+			x:        100,
+			y:        100,
+			velocity: vector.Vector2D{Y: 1},
+			agents:   []physics.Physic{newAgent(rng, physics.BulletAgent, 80, 100, physics.Box{W: 12, H: 4})},
+			check:    func(v vector.Vector2D) bool { return v.X > 0 },
+		},
+		{
+			name:     "left wall",
+			topo:     topology.Bounded{Width: 800, Height: 600},
+			x:        20,
+			y:        300,
+			velocity: vector.Vector2D{X: -3},
+			check:    func(v vector.Vector2D) bool { return v.X > -3 },
+		},
+		{
+			name:     "no wall in a toroidal world",
+			topo:     topology.Toroidal{Width: 800, Height: 600},
+			x:        20,
+			y:        300,
+			velocity: vector.Vector2D{X: -3},
+			check:    func(v vector.Vector2D) bool { return v.X == -3 },
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			vision := func(x, y float64) []physics.Physic {
+				return tt.agents
+			}
+			b := ai.NewBoid(logrus.New(), rand.New(rand.NewSource(1)), clock.New(60), tt.topo,
+				tt.x, tt.y, 800, 600, nil, nil, vision, flocking, false)
+			b.Init(tt.velocity)
+			b.Update(1.0 / 60)
+			if !tt.check(b.Velocity()) {
+				t.Errorf("unexpected velocity %v", b.Velocity())
+			}
+		})
+	}
+}
+
+func TestScare(t *testing.T) {
+	flocking := &ai.Flocking{MaxSpeed: 3, MaxForce: 0.3, FieldOfView: 360}
+	vision := func(x, y float64) []physics.Physic {
+		return nil
+	}
+	b := ai.NewBoid(logrus.New(), rand.New(rand.NewSource(1)), clock.New(60), topology.Infinite{},
+		100, 100, 800, 600, nil, nil, vision, flocking, false)
+	b.Init(vector.Vector2D{})
+	b.Scare(vector.Vector2D{X: 90, Y: 100}, 1, 2)
+
+	b.Update(1.0 / 60)
+	if b.Velocity().X <= 0 {
+		t.Fatalf("expected the boid to flee the threat, got velocity %v", b.Velocity())
+	}
+	// the panic fades away
+	for i := 0; i < 600; i++ {
+		b.Update(1.0 / 60)
+	}
+	before := b.Velocity()
+	b.Update(1.0 / 60)
+	if b.Velocity() != before {
+		t.Errorf("expected the boid to calm down, got velocity %v then %v", before, b.Velocity())
+	}
+}
@@ -4,135 +4,189 @@ import (
 	"math"
 	"math/rand"
 
-	// anonymous import for png decoder
-
-	"image/color"
-	_ "image/png"
-
-	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jtbonhomme/asteboids/internal/clock"
 	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/jtbonhomme/asteboids/internal/topology"
 	"github.com/jtbonhomme/asteboids/internal/vector"
 	"github.com/sirupsen/logrus"
 )
 
-const (
-	boidMaxForce     float64 = 0.3
-	boidMaxVelocity  float64 = 3.0
-	separationFactor float64 = 1.9
-	cohesionFactor   float64 = 1.5
-	alignmentFactor  float64 = 1.3
-)
+// minPanic is the panic force below which boids calm down.
+const minPanic float64 = 0.01
 
 // Boid is a PhysicalBody agent.
 // It represents a single autonomous agent.
 type Boid struct {
 	physics.Body
-}
-
-func rayVertices(x1, y1, x2, y2, x3, y3 float64) []ebiten.Vertex {
-	return []ebiten.Vertex{
-		{DstX: float32(x1), DstY: float32(y1), SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
-		{DstX: float32(x2), DstY: float32(y2), SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
-		{DstX: float32(x3), DstY: float32(y3), SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
-	}
+	flocking   *Flocking
+	panic      vector.Vector2D
+	panicDecay float64
 }
 
 // NewBoid creates a new Boid (PhysicalBody agent)
 func NewBoid(
 	log *logrus.Logger,
+	rng *rand.Rand,
+	clk *clock.Clock,
+	topo topology.Topology,
 	x, y,
 	screenWidth, screenHeight float64,
-	boidImage *ebiten.Image,
+	cbu physics.AgentUnregister,
+	boidImage render.Image,
 	vision physics.AgentVision,
+	flocking *Flocking,
 	debug bool) *Boid {
-	b := Boid{}
+	b := Boid{
+		flocking: flocking,
+	}
+	b.Rand = rng
+	b.Clock = clk
+	b.Topology = topo
 	b.AgentType = physics.BoidAgent
+	b.Unregister = cbu
 
-	b.Orientation = math.Pi / 32 * float64(rand.Intn(64))
+	b.Orientation = math.Pi / 32 * float64(b.Rand.Intn(64))
 
 	b.Init(vector.Vector2D{
-		X: boidMaxVelocity * math.Cos(b.Orientation),
-		Y: boidMaxVelocity * math.Sin(b.Orientation),
+		X: flocking.MaxSpeed * math.Cos(b.Orientation),
+		Y: flocking.MaxSpeed * math.Sin(b.Orientation),
 	})
-	b.LimitVelocity(boidMaxVelocity)
+	b.LimitVelocity(flocking.MaxSpeed)
 	b.Log = log
 
 	b.Move(vector.Vector2D{
 		X: x,
 		Y: y,
 	})
-	b.PhysicWidth = 10
-	b.PhysicHeight = 10
+	b.SetSize(10)
 	b.ScreenWidth = screenWidth
 	b.ScreenHeight = screenHeight
 
-	emptyImage := ebiten.NewImage(10, 10)
-	emptyImage.Fill(color.RGBA{100, 100, 200, 255})
-	op := &ebiten.DrawTrianglesOptions{}
-	op.Address = ebiten.AddressRepeat
-	op.CompositeMode = ebiten.CompositeModeSourceOut
-	b.Image = ebiten.NewImage(10, 10)
-	b.Image.DrawTriangles(
-		[]ebiten.Vertex{
-			{DstX: float32(0), DstY: float32(0), SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
-			{DstX: float32(10), DstY: float32(5), SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
-			{DstX: float32(5), DstY: float32(5), SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
-			{DstX: float32(0), DstY: float32(10), SrcX: 0, SrcY: 0, ColorR: 1, ColorG: 1, ColorB: 1, ColorA: 1},
-		},
-		[]uint16{0, 1, 2, 3, 1, 2},
-		emptyImage,
-		op,
-	)
+	b.Image = boidImage
 	b.Vision = vision
 	b.Debug = debug
 	return &b
 }
 
 // Update proceeds the game state.
-// Update is called every tick (1/60 [s] by default).
-func (b *Boid) Update() {
+// Update is called every physics step, dt seconds long (1/60 [s] by default).
+func (b *Boid) Update(dt float64) {
+	nearestAgent := b.Perceive(b.Vision(b.Position().X, b.Position().Y))
+	b.move(b.steer(nearestAgent), dt)
+}
 
+// move accelerates the boid and moves it dt seconds later.
+// A panicked boid is also pushed by its fading panic.
+func (b *Boid) move(acceleration vector.Vector2D, dt float64) {
+	// flocking parameters may be tuned at runtime
+	b.LimitVelocity(b.flocking.MaxSpeed)
+	if !b.panic.IsNil() {
+		acceleration.Add(b.panic)
+		b.panic.Multiply(math.Exp(-b.panicDecay * dt))
+		if b.panic.MagnitudeSquared() < minPanic*minPanic {
+			b.panic = vector.Vector2D{}
+		}
+	}
+	b.Accelerate(acceleration)
+	b.Integrate(dt)
+	b.UpdateOrientation()
+}
+
+// steer returns the sum of the flocking and avoidance forces, from the perceived agents.
+// Boids flock with the agents of their own type, according to their species affinity.
+func (b *Boid) steer(nearestAgent []physics.Physic) vector.Vector2D {
 	acceleration := vector.Vector2D{}
-	nearestAgent := b.Vision(b.Position().X, b.Position().Y)
 
-	cohesion := b.cohesion(nearestAgent)
-	cohesion.Multiply(cohesionFactor)
+	cohesion := b.cohesion(b.within(nearestAgent, b.flocking.Cohesion.Radius))
+	cohesion.Multiply(b.flocking.Cohesion.Weight)
 	acceleration.Add(cohesion)
 
-	separation := b.separate(nearestAgent)
-	separation.Multiply(separationFactor)
+	separation := b.separate(b.within(nearestAgent, b.flocking.Separation.Radius))
+	separation.Multiply(b.flocking.Separation.Weight)
 	acceleration.Add(separation)
 
-	alignment := b.align(nearestAgent)
-	alignment.Multiply(alignmentFactor)
+	alignment := b.align(b.within(nearestAgent, b.flocking.Alignment.Radius))
+	alignment.Multiply(b.flocking.Alignment.Weight)
 	acceleration.Add(alignment)
 
-	b.Accelerate(acceleration)
-	b.UpdateVelocity()
-	b.UpdateOrientation()
-	b.UpdatePosition()
+	avoidance := b.avoidObstacles(nearestAgent)
+	avoidance.Multiply(b.flocking.Avoidance.Weight)
+	acceleration.Add(avoidance)
+
+	flee := b.fleeBullets(nearestAgent)
+	flee.Multiply(b.flocking.Flee.Weight)
+	acceleration.Add(flee)
+
+	repulsion := b.repel(b.within(nearestAgent, b.flocking.Repulsion.Radius))
+	repulsion.Multiply(b.flocking.Repulsion.Weight)
+	acceleration.Add(repulsion)
+
+	walls := b.avoidWalls()
+	walls.Multiply(b.flocking.Walls.Weight)
+	acceleration.Add(walls)
+
+	return acceleration
 }
 
 // Draw draws the game screen.
 // Draw is called every frame (typically 1/60[s] for 60Hz display).
-func (b *Boid) Draw(screen *ebiten.Image) {
+func (b *Boid) Draw(screen render.Screen) {
 	defer b.Body.Draw(screen)
-	nearestAgent := b.Vision(b.Position().X, b.Position().Y)
-	b.LinkAgents(screen, nearestAgent, []string{physics.BoidAgent})
+	nearestAgent := b.Perceive(b.Vision(b.Position().X, b.Position().Y))
+	b.LinkAgents(screen, nearestAgent, []string{b.Type()})
+	if b.Debug {
+		b.drawViewCone(screen)
+	}
+}
+
+// Scare makes the boid panic: it is pushed away from a threat with a force,
+// which decays by a ratio per second.
+func (b *Boid) Scare(threat vector.Vector2D, force, decay float64) {
+	away := b.World().Delta(threat, b.Position())
+	if away.IsNil() {
+		return
+	}
+	away.Normalize()
+	away.Multiply(force)
+	b.panic.Add(away)
+	b.panicDecay = decay
+}
+
+// MaxSpeed returns the boid maximum velocity (in pixels per 1/60 [s]).
+func (b *Boid) MaxSpeed() float64 {
+	return b.flocking.MaxSpeed
+}
+
+// MaxForce returns the boid maximum steering force.
+func (b *Boid) MaxForce() float64 {
+	return b.flocking.MaxForce
+}
+
+// within returns the agents closer than radius.
+func (b *Boid) within(agents []physics.Physic, radius float64) []physics.Physic {
+	result := []physics.Physic{}
+	for _, agent := range agents {
+		if b.World().Distance(b.Position(), agent.Position()) < radius {
+			result = append(result, agent)
+		}
+	}
+	return result
 }
 
 func (b *Boid) seek(target vector.Vector2D) vector.Vector2D {
-	desired := b.Position()
-	desired.Subtract(target)
+	desired := b.World().Delta(target, b.Position())
 	desired.Normalize()
-	desired.Multiply(boidMaxVelocity)
+	desired.Multiply(b.flocking.MaxSpeed)
 	steer := b.Velocity()
 	steer.Subtract(desired)
-	steer.Limit(boidMaxForce)
+	steer.Limit(b.flocking.MaxForce)
 	return steer
 }
 
 // cohesion returns the force imposed by flocking cohesion rule.
+// The center of the neighbours is computed from their displacement,
+// so that a flock spread across the world edges keeps a single center.
 func (b *Boid) cohesion(agents []physics.Physic) vector.Vector2D {
 	result := vector.Vector2D{
 		X: 0,
@@ -140,13 +194,16 @@ func (b *Boid) cohesion(agents []physics.Physic) vector.Vector2D {
 	}
 	var nBoids float64 = 0.0
 	for _, agent := range agents {
-		if agent.Type() == physics.BoidAgent && agent.ID() != b.ID() {
-			nBoids++
-			result.Add(agent.Position())
+		if a := b.affinity(agent); a > 0 && agent.ID() != b.ID() {
+			nBoids += a
+			delta := b.World().Delta(b.Position(), agent.Position())
+			delta.Multiply(a)
+			result.Add(delta)
 		}
 	}
 	if nBoids > 0 {
 		result.Divide(nBoids)
+		result.Add(b.Position())
 		result = b.seek(result)
 	}
 	return result
@@ -160,22 +217,22 @@ func (b *Boid) separate(agents []physics.Physic) vector.Vector2D {
 	}
 	var nBoids float64 = 0.0
 	for _, agent := range agents {
-		if /*agent.Type() == physics.BoidAgent && */ agent.ID() != b.ID() {
-			nBoids++
-			d := b.Position().Distance(agent.Position())
-			diff := b.Position()
-			diff.Subtract(agent.Position())
+		if a := b.affinity(agent); a > 0 && agent.ID() != b.ID() {
+			nBoids += a
+			d := b.World().Distance(b.Position(), agent.Position())
+			diff := b.World().Delta(agent.Position(), b.Position())
 			diff.Normalize()
 			diff.Divide(d)
+			diff.Multiply(a)
 			result.Add(diff)
 		}
 	}
 	if nBoids > 0 {
 		result.Divide(nBoids)
 		result.Normalize()
-		result.Multiply(boidMaxVelocity)
+		result.Multiply(b.flocking.MaxSpeed)
 		result.Subtract(b.Velocity())
-		result.Limit(boidMaxForce)
+		result.Limit(b.flocking.MaxForce)
 	}
 	return result
 }
@@ -188,16 +245,18 @@ func (b *Boid) align(agents []physics.Physic) vector.Vector2D {
 	}
 	var nBoids float64 = 0.0
 	for _, agent := range agents {
-		if agent.Type() == physics.BoidAgent && agent.ID() != b.ID() {
-			nBoids++
-			result.Add(agent.Velocity())
+		if a := b.affinity(agent); a > 0 && agent.ID() != b.ID() {
+			nBoids += a
+			velocity := agent.Velocity()
+			velocity.Multiply(a)
+			result.Add(velocity)
 		}
 	}
 	if nBoids > 0 {
 		result.Divide(nBoids)
-		result.Multiply(boidMaxVelocity)
+		result.Multiply(b.flocking.MaxSpeed)
 		result.Subtract(b.Velocity())
-		result.Limit(boidMaxForce)
+		result.Limit(b.flocking.MaxForce)
 	}
 	return result
 }
@@ -0,0 +1,52 @@
+package ai
+
+// Rule holds the parameters of a flocking rule.
+type Rule struct {
+	// Radius is the distance (in pixels) within which neighbours are considered by the rule.
+	Radius float64
+	// Weight is the factor of the rule steering force in the boid acceleration.
+	Weight float64
+}
+
+// Flocking holds the flocking parameters of boids.
+// Boids keep a reference to their parameters, so that they can be tuned while boids fly.
+type Flocking struct {
+	// Species is the name of the boids species flying with these parameters.
+	Species string
+	// Affinity of the species towards other species, from -1 (flee them) to 1 (flock with them).
+	// Boids flock with their own species, and ignore other species by default.
+	Affinity map[string]float64
+	// MaxSpeed is the maximum boid velocity (in pixels per 1/60 [s]).
+	MaxSpeed float64
+	// MaxForce is the maximum steering force of each rule.
+	MaxForce float64
+	// Separation steers to avoid crowding neighbours.
+	Separation Rule
+	// Cohesion steers towards the center of neighbours.
+	Cohesion Rule
+	// Alignment steers towards the average heading of neighbours.
+	Alignment Rule
+	// Avoidance steers away from asteroids and rubbles ahead, its radius is the look-ahead distance.
+	Avoidance Rule
+	// Flee steers away from bullets, its radius is the threat distance.
+	Flee Rule
+	// Repulsion steers away from the boids of disliked species (negative affinity).
+	Repulsion Rule
+	// Walls steers away from the walls of a bounded world, its radius is the distance to walls.
+	Walls Rule
+	// FieldOfView is the angle (in degrees) of the view cone of boids, centered on their heading.
+	FieldOfView float64
+	// Occlusion hides the agents behind asteroids and rubbles.
+	Occlusion bool
+}
+
+// MaxRadius returns the largest radius of the flocking rules.
+func (f *Flocking) MaxRadius() float64 {
+	radius := 0.0
+	for _, r := range []float64{f.Separation.Radius, f.Cohesion.Radius, f.Alignment.Radius, f.Avoidance.Radius, f.Flee.Radius, f.Repulsion.Radius} {
+		if r > radius {
+			radius = r
+		}
+	}
+	return radius
+}
@@ -0,0 +1,137 @@
+package ai
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/jtbonhomme/asteboids/internal/clock"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+	"github.com/sirupsen/logrus"
+)
+
+// Number of inputs (sensors) and outputs (actions) of a neural boid brain.
+const (
+	Sensors int = 12
+	Actions int = 2
+)
+
+// Brain decides how a boid steers, from what it senses.
+type Brain interface {
+	// Think returns the brain outputs, between -1 and 1, for the given inputs.
+	Think(inputs []float64) []float64
+}
+
+// NeuralBoid is a boid driven by a brain instead of flocking rules.
+// Its brain senses the neighbours in the boid vision, and outputs its steering force.
+type NeuralBoid struct {
+	Boid
+	brain Brain
+}
+
+// NewNeuralBoid creates a new NeuralBoid (PhysicalBody agent)
+// Flocking parameters only set its speed, force and field of view.
+func NewNeuralBoid(
+	log *logrus.Logger,
+	rng *rand.Rand,
+	clk *clock.Clock,
+	topo topology.Topology,
+	x, y,
+	screenWidth, screenHeight float64,
+	cbu physics.AgentUnregister,
+	boidImage render.Image,
+	vision physics.AgentVision,
+	flocking *Flocking,
+	brain Brain,
+	debug bool) *NeuralBoid {
+	return &NeuralBoid{
+		Boid:  *NewBoid(log, rng, clk, topo, x, y, screenWidth, screenHeight, cbu, boidImage, vision, flocking, debug),
+		brain: brain,
+	}
+}
+
+// Update proceeds the game state.
+// Update is called every physics step, dt seconds long (1/60 [s] by default).
+func (n *NeuralBoid) Update(dt float64) {
+	nearestAgent := n.Perceive(n.Vision(n.Position().X, n.Position().Y))
+	outputs := n.brain.Think(n.Sense(nearestAgent))
+	if len(outputs) < Actions {
+		n.move(vector.Vector2D{}, dt)
+		return
+	}
+	// outputs are the steering force in the boid frame: forward and left
+	cos, sin := math.Cos(n.Orientation), math.Sin(n.Orientation)
+	acceleration := vector.Vector2D{
+		X: outputs[0]*cos - outputs[1]*sin,
+		Y: outputs[0]*sin + outputs[1]*cos,
+	}
+	acceleration.Multiply(n.flocking.MaxForce)
+	n.move(acceleration, dt)
+}
+
+// Sense returns the brain inputs, from the agents the boid perceives.
+// Positions and velocities are given in the boid frame, relative to its range and speed:
+// its speed, the center, average velocity and count of its flock mates,
+// and the proximity of the closest flock mate, obstacle and threat.
+func (n *NeuralBoid) Sense(agents []physics.Physic) []float64 {
+	inputs := make([]float64, Sensors)
+	radius := n.flocking.MaxRadius()
+	if radius <= 0 || n.flocking.MaxSpeed <= 0 {
+		return inputs
+	}
+	cos, sin := math.Cos(n.Orientation), math.Sin(n.Orientation)
+	local := func(v vector.Vector2D, scale float64) (float64, float64) {
+		return (v.X*cos + v.Y*sin) / scale, (-v.X*sin + v.Y*cos) / scale
+	}
+	velocity := n.Velocity()
+	inputs[0] = math.Sqrt(velocity.MagnitudeSquared()) / n.flocking.MaxSpeed
+
+	center, heading := vector.Vector2D{}, vector.Vector2D{}
+	mates := 0.0
+	var mate, obstacle, threat vector.Vector2D
+	closestMate, closestObstacle, closestThreat := radius, radius, radius
+	for _, agent := range agents {
+		delta := n.World().Delta(n.Position(), agent.Position())
+		d := math.Sqrt(delta.MagnitudeSquared())
+		if d >= radius || d == 0 {
+			continue
+		}
+		switch {
+		case n.affinity(agent) > 0:
+			mates++
+			center.Add(delta)
+			heading.Add(agent.Velocity())
+			if d < closestMate {
+				closestMate, mate = d, delta
+			}
+		case agent.Type() == physics.AsteroidAgent || agent.Type() == physics.RubbleAgent:
+			if d < closestObstacle {
+				closestObstacle, obstacle = d, delta
+			}
+		case agent.Type() == physics.BulletAgent || agent.Type() == physics.PredatorAgent:
+			if d < closestThreat {
+				closestThreat, threat = d, delta
+			}
+		}
+	}
+	if mates > 0 {
+		center.Divide(mates)
+		heading.Divide(mates)
+		inputs[1], inputs[2] = local(center, radius)
+		inputs[3], inputs[4] = local(heading, n.flocking.MaxSpeed)
+		inputs[5] = math.Min(mates/10, 1)
+	}
+	// proximity goes from 0 (out of range) to 1 (contact), in the direction of the agent
+	proximity := func(delta vector.Vector2D, d float64) (float64, float64) {
+		if delta.IsNil() {
+			return 0, 0
+		}
+		return local(delta, d/(1-d/radius))
+	}
+	inputs[6], inputs[7] = proximity(mate, closestMate)
+	inputs[8], inputs[9] = proximity(obstacle, closestObstacle)
+	inputs[10], inputs[11] = proximity(threat, closestThreat)
+	return inputs
+}
@@ -0,0 +1,105 @@
+// Package neural implements a small feed-forward neural network, trained by neuroevolution.
+package neural
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"os"
+)
+
+// Network is a fully connected feed-forward neural network, with tanh activations.
+// Weights are stored layer after layer, each neuron weights followed by its bias.
+type Network struct {
+	Layers  []int     `json:"layers"`
+	Weights []float64 `json:"weights"`
+}
+
+// New creates a network with the given number of neurons per layer, from inputs to outputs.
+// All weights are zero.
+func New(layers ...int) *Network {
+	n := &Network{Layers: layers}
+	n.Weights = make([]float64, n.size())
+	return n
+}
+
+// size returns the number of weights of the network.
+func (n *Network) size() int {
+	size := 0
+	for i := 1; i < len(n.Layers); i++ {
+		size += (n.Layers[i-1] + 1) * n.Layers[i]
+	}
+	return size
+}
+
+// Randomize sets all weights randomly, between -1 and 1.
+func (n *Network) Randomize(rng *rand.Rand) {
+	for i := range n.Weights {
+		n.Weights[i] = 2*rng.Float64() - 1
+	}
+}
+
+// Think propagates inputs through the network, and returns its outputs, between -1 and 1.
+// Missing inputs are zero.
+func (n *Network) Think(inputs []float64) []float64 {
+	if len(n.Layers) == 0 {
+		return nil
+	}
+	values := make([]float64, n.Layers[0])
+	copy(values, inputs)
+	w := 0
+	for l := 1; l < len(n.Layers); l++ {
+		next := make([]float64, n.Layers[l])
+		for j := range next {
+			sum := 0.0
+			for _, v := range values {
+				sum += n.Weights[w] * v
+				w++
+			}
+			// bias
+			sum += n.Weights[w]
+			w++
+			next[j] = math.Tanh(sum)
+		}
+		values = next
+	}
+	return values
+}
+
+// Save writes out the network as JSON.
+func (n *Network) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(n)
+}
+
+// Load reads a network saved as JSON, and checks its layers and weights.
+func Load(r io.Reader) (*Network, error) {
+	n := &Network{}
+	err := json.NewDecoder(r).Decode(n)
+	if err != nil {
+		return nil, err
+	}
+	if len(n.Layers) < 2 {
+		return nil, fmt.Errorf("invalid network: %d layers, want at least 2", len(n.Layers))
+	}
+	for _, l := range n.Layers {
+		if l <= 0 {
+			return nil, fmt.Errorf("invalid network: layers %v, all layers must hold neurons", n.Layers)
+		}
+	}
+	if len(n.Weights) != n.size() {
+		return nil, fmt.Errorf("invalid network: %d weights for layers %v", len(n.Weights), n.Layers)
+	}
+	return n, nil
+}
+
+// LoadFile reads a network saved as JSON in a file.
+func LoadFile(name string) (*Network, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Load(f)
+}
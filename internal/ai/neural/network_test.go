@@ -0,0 +1,95 @@
+package neural_test
+
+import (
+	"bytes"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/ai/neural"
+)
+
+func TestThink(t *testing.T) {
+	tests := []struct {
+		name    string
+		layers  []int
+		weights []float64
+		inputs  []float64
+		want    []float64
+	}{
+		{
+			name:    "zero weights",
+			layers:  []int{2, 1},
+			weights: []float64{0, 0, 0},
+			inputs:  []float64{1, 1},
+			want:    []float64{0},
+		},
+		{
+			name:    "weighted sum and bias",
+			layers:  []int{2, 1},
+			weights: []float64{1, -1, 0.5},
+			inputs:  []float64{2, 1},
+			want:    []float64{math.Tanh(1.5)},
+		},
+		{
+			name:    "hidden layer",
+			layers:  []int{1, 2, 1},
+			weights: []float64{1, 0, -1, 0, 1, 1, 0},
+			inputs:  []float64{1},
+			want:    []float64{math.Tanh(math.Tanh(1) + math.Tanh(-1))},
+		},
+		{
+			name:    "missing inputs",
+			layers:  []int{2, 1},
+			weights: []float64{1, 1, 0},
+			inputs:  []float64{1},
+			want:    []float64{math.Tanh(1)},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			n := &neural.Network{Layers: tt.layers, Weights: tt.weights}
+			got := n.Think(tt.inputs)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Think() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Errorf("Think() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	n := neural.New(3, 4, 2)
+	if len(n.Weights) != 4*4+5*2 {
+		t.Fatalf("got %d weights, want %d", len(n.Weights), 4*4+5*2)
+	}
+	n.Randomize(rand.New(rand.NewSource(1)))
+
+	var buf bytes.Buffer
+	if err := n.Save(&buf); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	loaded, err := neural.Load(&buf)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	inputs := []float64{0.5, -1, 0.25}
+	if got, want := loaded.Think(inputs), n.Think(inputs); got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("loaded network thinks %v, want %v", got, want)
+	}
+
+	if _, err := neural.Load(strings.NewReader(`{"layers":[3,2],"weights":[1,2]}`)); err == nil {
+		t.Error("expected an error for a network with missing weights")
+	}
+	if _, err := neural.Load(strings.NewReader(`{"layers":[-1,5,2],"weights":[0,0,0,0,0,0,0,0,0,0,0,0]}`)); err == nil {
+		t.Error("expected an error for a network with a negative layer")
+	}
+}
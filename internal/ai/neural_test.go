@@ -0,0 +1,88 @@
+package ai_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/ai"
+	"github.com/jtbonhomme/asteboids/internal/clock"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+	"github.com/sirupsen/logrus"
+)
+
+// brain always thinks the same outputs.
+type brain []float64
+
+func (b brain) Think(inputs []float64) []float64 { return b }
+
+func TestNeuralBoid(t *testing.T) {
+	tests := []struct {
+		name    string
+		outputs brain
+		// check returns true if the boid velocity steers the expected way
+		check func(v vector.Vector2D) bool
+	}{
+		{
+			name:    "forward",
+			outputs: brain{1, 0},
+			check:   func(v vector.Vector2D) bool { return v.X > 0 && v.Y == 0 },
+		},
+		{
+			name:    "left",
+			outputs: brain{0, 1},
+			check:   func(v vector.Vector2D) bool { return v.Y > 0 },
+		},
+		{
+			name:    "no output",
+			outputs: brain{},
+			check:   func(v vector.Vector2D) bool { return v.IsNil() },
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			flocking := &ai.Flocking{Species: "neural", MaxSpeed: 3, MaxForce: 0.3, Cohesion: ai.Rule{Radius: 75}, FieldOfView: 360}
+			vision := func(x, y float64) []physics.Physic { return nil }
+			b := ai.NewNeuralBoid(logrus.New(), rand.New(rand.NewSource(1)), clock.New(60), topology.Infinite{},
+				100, 100, 800, 600, nil, nil, vision, flocking, tt.outputs, false)
+			// heading east, at rest
+			b.Orientation = 0
+			b.Init(vector.Vector2D{})
+			b.Update(1.0 / 60)
+			if v := b.Velocity(); !tt.check(v) {
+				t.Errorf("unexpected velocity %+v", v)
+			}
+		})
+	}
+}
+
+func TestSense(t *testing.T) {
+	flocking := &ai.Flocking{Species: "neural", MaxSpeed: 3, MaxForce: 0.3, Cohesion: ai.Rule{Radius: 100}, FieldOfView: 360}
+	b := ai.NewNeuralBoid(logrus.New(), rand.New(rand.NewSource(1)), clock.New(60), topology.Infinite{},
+		100, 100, 800, 600, nil, nil, nil, flocking, brain{}, false)
+	b.Orientation = 0
+	b.Init(vector.Vector2D{X: 3})
+	mate := ai.NewBoid(logrus.New(), rand.New(rand.NewSource(2)), clock.New(60), topology.Infinite{},
+		150, 100, 800, 600, nil, nil, nil, flocking, false)
+	mate.Init(vector.Vector2D{Y: 3})
+
+	inputs := b.Sense([]physics.Physic{mate})
+	if len(inputs) != ai.Sensors {
+		t.Fatalf("got %d inputs, want %d", len(inputs), ai.Sensors)
+	}
+	want := map[int]float64{
+		0: 1,   // full speed
+		1: 0.5, // mate center ahead
+		4: 1,   // mate heading left
+		6: 0.5, // closest mate ahead, half way
+	}
+	for i, v := range want {
+		if d := inputs[i] - v; d > 1e-9 || d < -1e-9 {
+			t.Errorf("input %d = %f, want %f (inputs %v)", i, inputs[i], v, inputs)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"image/color"
+	"math"
+
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+const viewConeSegments int = 16
+
+// Perceive returns the agents the boid can see among the ones in its vision radius:
+// agents must be in its field of view and, with occlusion, not hidden behind an asteroid.
+func (b *Boid) Perceive(agents []physics.Physic) []physics.Physic {
+	fov := b.flocking.FieldOfView * math.Pi / 180
+	result := []physics.Physic{}
+	for _, agent := range agents {
+		if agent.ID() == b.ID() {
+			continue
+		}
+		delta := b.World().Delta(b.Position(), agent.Position())
+		if fov < 2*math.Pi && math.Abs(angleBetween(b.heading(), delta.Theta())) > fov/2 {
+			continue
+		}
+		if b.flocking.Occlusion && b.occluded(agent, delta, agents) {
+			continue
+		}
+		result = append(result, agent)
+	}
+	return result
+}
+
+// heading returns the direction the boid looks at.
+func (b *Boid) heading() float64 {
+	if b.Velocity().IsNil() {
+		return b.Orientation
+	}
+	return b.Velocity().Theta()
+}
+
+// occluded returns true if an asteroid or a rubble stands between the boid and the agent.
+func (b *Boid) occluded(agent physics.Physic, delta vector.Vector2D, obstacles []physics.Physic) bool {
+	for _, obstacle := range obstacles {
+		if obstacle.ID() == agent.ID() ||
+			(obstacle.Type() != physics.AsteroidAgent && obstacle.Type() != physics.RubbleAgent) {
+			continue
+		}
+		center := b.World().Delta(b.Position(), obstacle.Position())
+		if segmentCircleIntersect(delta, center, obstacle.CollisionShape().BoundingRadius()) {
+			return true
+		}
+	}
+	return false
+}
+
+// segmentCircleIntersect returns true if the segment from the origin to end crosses the circle.
+func segmentCircleIntersect(end, center vector.Vector2D, radius float64) bool {
+	length := end.MagnitudeSquared()
+	if length == 0 {
+		return false
+	}
+	// closest point of the segment to the circle center
+	t := (center.X*end.X + center.Y*end.Y) / length
+	t = math.Max(0, math.Min(1, t))
+	dx := end.X*t - center.X
+	dy := end.Y*t - center.Y
+	return dx*dx+dy*dy < radius*radius
+}
+
+// angleBetween returns the signed angle from a to b, in [-π, π].
+func angleBetween(a, b float64) float64 {
+	return math.Remainder(b-a, 2*math.Pi)
+}
+
+// drawViewCone draws the boid field of view, up to its largest flocking radius.
+func (b *Boid) drawViewCone(screen render.Screen) {
+	clr := color.Gray16{0x2264}
+	radius := b.flocking.MaxRadius()
+	fov := math.Min(b.flocking.FieldOfView*math.Pi/180, 2*math.Pi)
+	x, y := b.Position().X, b.Position().Y
+	start := b.heading() - fov/2
+
+	prevX, prevY := x+radius*math.Cos(start), y+radius*math.Sin(start)
+	if fov < 2*math.Pi {
+		screen.DrawLine(x, y, prevX, prevY, clr)
+	}
+	for i := 1; i <= viewConeSegments; i++ {
+		theta := start + fov*float64(i)/float64(viewConeSegments)
+		nextX, nextY := x+radius*math.Cos(theta), y+radius*math.Sin(theta)
+		screen.DrawLine(prevX, prevY, nextX, nextY, clr)
+		prevX, prevY = nextX, nextY
+	}
+	if fov < 2*math.Pi {
+		screen.DrawLine(x, y, prevX, prevY, clr)
+	}
+}
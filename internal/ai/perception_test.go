@@ -0,0 +1,67 @@
+package ai_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/ai"
+	"github.com/jtbonhomme/asteboids/internal/clock"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+	"github.com/sirupsen/logrus"
+)
+
+func newAgent(rng *rand.Rand, agentType string, x, y float64, shape physics.Shape) *physics.Body {
+	b := physics.NewBody(x, y, 10, 10)
+	b.Rand = rng
+	b.AgentType = agentType
+	b.Topology = topology.Infinite{}
+	b.Shape = shape
+	b.Init(vector.Vector2D{})
+	return b
+}
+
+func TestPerceive(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	ahead := newAgent(rng, physics.BoidAgent, 110, 100, nil)
+	behind := newAgent(rng, physics.BoidAgent, 90, 100, nil)
+	side := newAgent(rng, physics.BoidAgent, 100, 110, nil)
+	hidden := newAgent(rng, physics.BoidAgent, 200, 100, nil)
+	asteroid := newAgent(rng, physics.AsteroidAgent, 150, 100, physics.Circle{R: 10})
+	agents := []physics.Physic{ahead, behind, side, hidden, asteroid}
+
+	tests := []struct {
+		name        string
+		fieldOfView float64
+		occlusion   bool
+		want        []physics.Physic
+	}{
+		{name: "full circle", fieldOfView: 360, want: []physics.Physic{ahead, behind, side, hidden, asteroid}},
+		{name: "half circle", fieldOfView: 200, want: []physics.Physic{ahead, side, hidden, asteroid}},
+		{name: "narrow cone", fieldOfView: 60, want: []physics.Physic{ahead, hidden, asteroid}},
+		{name: "occlusion", fieldOfView: 360, occlusion: true, want: []physics.Physic{ahead, behind, side, asteroid}},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			flocking := &ai.Flocking{MaxSpeed: 3, FieldOfView: tt.fieldOfView, Occlusion: tt.occlusion}
+			b := ai.NewBoid(logrus.New(), rand.New(rand.NewSource(1)), clock.New(60), topology.Infinite{},
+				100, 100, 800, 600, nil, nil, nil, flocking, false)
+			// look towards +X
+			b.Init(vector.Vector2D{X: 1})
+
+			got := b.Perceive(append(agents, b))
+			if len(got) != len(tt.want) {
+				t.Fatalf("perceived %d agents, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i].ID() != tt.want[i].ID() {
+					t.Errorf("perceived agent %d is %s, want %s", i, got[i].ID(), tt.want[i].ID())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package ai
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/jtbonhomme/asteboids/internal/ai/steering"
+	"github.com/jtbonhomme/asteboids/internal/clock"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/sirupsen/logrus"
+)
+
+// Hunting holds the hunting parameters of predators.
+type Hunting struct {
+	// Weight is the factor of the pursuit steering force in the predator acceleration.
+	Weight float64
+	// ArriveRadius is the distance (in pixels) from the prey below which predators slow down.
+	ArriveRadius float64
+	// MaxPrediction is the maximum time (in 1/60 [s]) predators anticipate the prey move.
+	MaxPrediction float64
+}
+
+// Predator is a boid which hunts the starship.
+// Predators flock with each other, and pursue the starship once they see it.
+type Predator struct {
+	Boid
+	hunting *Hunting
+}
+
+// NewPredator creates a new Predator (PhysicalBody agent)
+// vision must return the agents within the hunting radius.
+func NewPredator(
+	log *logrus.Logger,
+	rng *rand.Rand,
+	clk *clock.Clock,
+	topo topology.Topology,
+	x, y,
+	screenWidth, screenHeight float64,
+	cbu physics.AgentUnregister,
+	predatorImage render.Image,
+	vision physics.AgentVision,
+	flocking *Flocking,
+	hunting *Hunting,
+	debug bool) *Predator {
+	p := Predator{
+		Boid:    *NewBoid(log, rng, clk, topo, x, y, screenWidth, screenHeight, cbu, predatorImage, vision, flocking, debug),
+		hunting: hunting,
+	}
+	p.AgentType = physics.PredatorAgent
+	p.SetSize(14)
+	return &p
+}
+
+// Update proceeds the game state.
+// Update is called every physics step, dt seconds long (1/60 [s] by default).
+func (p *Predator) Update(dt float64) {
+	nearestAgent := p.Perceive(p.Vision(p.Position().X, p.Position().Y))
+	acceleration := p.steer(nearestAgent)
+	if prey := p.closest(nearestAgent, physics.StarshipAgent); prey != nil {
+		pursuit := steering.Pursue{
+			Target:        prey,
+			MaxPrediction: p.hunting.MaxPrediction,
+			ArriveRadius:  p.hunting.ArriveRadius,
+		}.Steer(p)
+		pursuit.Multiply(p.hunting.Weight)
+		acceleration.Add(pursuit)
+	}
+	p.move(acceleration, dt)
+}
+
+// Draw draws the game screen.
+// Draw is called every frame (typically 1/60[s] for 60Hz display).
+func (p *Predator) Draw(screen render.Screen) {
+	defer p.Body.Draw(screen)
+	nearestAgent := p.Perceive(p.Vision(p.Position().X, p.Position().Y))
+	p.LinkAgents(screen, nearestAgent, []string{physics.StarshipAgent})
+	if p.Debug {
+		p.drawViewCone(screen)
+	}
+}
+
+// closest returns the closest agent of a given type, or nil.
+func (p *Predator) closest(agents []physics.Physic, agentType string) physics.Physic {
+	var result physics.Physic
+	closest := math.Inf(1)
+	for _, agent := range agents {
+		if agent.Type() != agentType {
+			continue
+		}
+		if d := p.World().Distance(p.Position(), agent.Position()); d < closest {
+			closest = d
+			result = agent
+		}
+	}
+	return result
+}
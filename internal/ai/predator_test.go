@@ -0,0 +1,67 @@
+package ai_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/ai"
+	"github.com/jtbonhomme/asteboids/internal/clock"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+	"github.com/sirupsen/logrus"
+)
+
+func TestPredatorPursuit(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	flocking := &ai.Flocking{MaxSpeed: 3, MaxForce: 0.3, FieldOfView: 360}
+	hunting := &ai.Hunting{Weight: 1, ArriveRadius: 20, MaxPrediction: 30}
+
+	tests := []struct {
+		name     string
+		ship     *physics.Body
+		velocity vector.Vector2D
+		// check returns true if the predator velocity steers the expected way
+		check func(v vector.Vector2D) bool
+	}{
+		{
+			name:  "ship above",
+			ship:  newAgent(rng, physics.StarshipAgent, 100, 0, nil),
+			check: func(v vector.Vector2D) bool { return v.Y < 0 },
+		},
+		{
+			// the ship flies to the right, the predator anticipates its move
+			name:     "moving ship",
+			ship:     newAgent(rng, physics.StarshipAgent, 100, 0, nil),
+			velocity: vector.Vector2D{X: 3},
+			check:    func(v vector.Vector2D) bool { return v.Y < 0 && v.X > 0 },
+		},
+		{
+			name:  "no ship",
+			check: func(v vector.Vector2D) bool { return v.IsNil() },
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			agents := []physics.Physic{}
+			if tt.ship != nil {
+				ship := *tt.ship
+				ship.Init(tt.velocity)
+				agents = append(agents, &ship)
+			}
+			vision := func(x, y float64) []physics.Physic {
+				return agents
+			}
+			p := ai.NewPredator(logrus.New(), rand.New(rand.NewSource(1)), clock.New(60), topology.Infinite{},
+				100, 100, 800, 600, nil, nil, vision, flocking, hunting, false)
+			p.Init(vector.Vector2D{})
+			p.Update(1.0 / 60)
+			if !tt.check(p.Velocity()) {
+				t.Errorf("unexpected velocity %v", p.Velocity())
+			}
+		})
+	}
+}
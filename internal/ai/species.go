@@ -0,0 +1,66 @@
+package ai
+
+import (
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+// Species returns the name of the boid species.
+func (b *Boid) Species() string {
+	return b.flocking.Species
+}
+
+// SetSize sets the boid dimension, and its triangle collision shape.
+func (b *Boid) SetSize(size float64) {
+	b.PhysicWidth = size
+	b.PhysicHeight = size
+	b.Shape = physics.Polygon{
+		Points: []vector.Vector2D{
+			{X: size / 2, Y: 0},
+			{X: -size / 2, Y: -size / 2},
+			{X: -size / 2, Y: size / 2},
+		},
+	}
+}
+
+// affinity returns the boid affinity towards an agent, from -1 (flee it) to 1 (flock with it).
+// Boids flock with the boids of their own species.
+func (b *Boid) affinity(agent physics.Physic) float64 {
+	if agent.Type() != b.Type() {
+		return 0
+	}
+	other, ok := agent.(interface{ Species() string })
+	if !ok || other.Species() == b.Species() {
+		return 1
+	}
+	return b.flocking.Affinity[other.Species()]
+}
+
+// repel returns the force steering the boid away from the boids of the species it dislikes.
+// Closest and most disliked boids weigh more.
+func (b *Boid) repel(agents []physics.Physic) vector.Vector2D {
+	result := vector.Vector2D{}
+	var nBoids float64 = 0.0
+	for _, agent := range agents {
+		a := b.affinity(agent)
+		if a >= 0 {
+			continue
+		}
+		d := b.World().Distance(b.Position(), agent.Position())
+		if d == 0 {
+			continue
+		}
+		nBoids++
+		diff := b.World().Delta(agent.Position(), b.Position())
+		diff.Normalize()
+		diff.Multiply(-a / d)
+		result.Add(diff)
+	}
+	if nBoids > 0 {
+		result.Normalize()
+		result.Multiply(b.flocking.MaxSpeed)
+		result.Subtract(b.Velocity())
+		result.Limit(b.flocking.MaxForce)
+	}
+	return result
+}
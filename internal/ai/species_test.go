@@ -0,0 +1,68 @@
+package ai_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/ai"
+	"github.com/jtbonhomme/asteboids/internal/clock"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+	"github.com/sirupsen/logrus"
+)
+
+func TestSpeciesAffinity(t *testing.T) {
+	other := &ai.Flocking{Species: "other", MaxSpeed: 3, MaxForce: 0.3, FieldOfView: 360}
+
+	tests := []struct {
+		name     string
+		affinity map[string]float64
+		// check returns true if the boid velocity steers the expected way
+		check func(v vector.Vector2D) bool
+	}{
+		{
+			name:     "flock with",
+			affinity: map[string]float64{"other": 1},
+			check:    func(v vector.Vector2D) bool { return v.X > 0 },
+		},
+		{
+			name:     "flee",
+			affinity: map[string]float64{"other": -1},
+			check:    func(v vector.Vector2D) bool { return v.X < 0 },
+		},
+		{
+			name:  "ignore",
+			check: func(v vector.Vector2D) bool { return v.IsNil() },
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			flocking := &ai.Flocking{
+				Species:     "boid",
+				Affinity:    tt.affinity,
+				MaxSpeed:    3,
+				MaxForce:    0.3,
+				Cohesion:    ai.Rule{Radius: 75, Weight: 1},
+				Flee:        ai.Rule{Radius: 50, Weight: 1},
+				Repulsion:   ai.Rule{Radius: 75, Weight: 1},
+				FieldOfView: 360,
+			}
+			neighbour := ai.NewBoid(logrus.New(), rand.New(rand.NewSource(1)), clock.New(60), topology.Infinite{},
+				130, 100, 800, 600, nil, nil, nil, other, false)
+			vision := func(x, y float64) []physics.Physic {
+				return []physics.Physic{neighbour}
+			}
+			b := ai.NewBoid(logrus.New(), rand.New(rand.NewSource(2)), clock.New(60), topology.Infinite{},
+				100, 100, 800, 600, nil, nil, vision, flocking, false)
+			b.Init(vector.Vector2D{})
+			b.Update(1.0 / 60)
+			if !tt.check(b.Velocity()) {
+				t.Errorf("unexpected velocity %v", b.Velocity())
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package steering
+
+import (
+	"math"
+
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+// Weighted is a behaviour with its weight in a combination.
+type Weighted struct {
+	Behaviour
+	Weight float64
+}
+
+// Blend combines behaviours by summing their weighted forces.
+// The sum is limited to the vehicle maximum force.
+type Blend []Weighted
+
+// Steer returns the blended steering force.
+func (b Blend) Steer(v Vehicle) vector.Vector2D {
+	result := vector.Vector2D{}
+	for _, w := range b {
+		force := w.Steer(v)
+		force.Multiply(w.Weight)
+		result.Add(force)
+	}
+	result.Limit(v.MaxForce())
+	return result
+}
+
+// Priority combines behaviours by order of priority.
+// Weighted forces are accumulated in order until the vehicle maximum force is used up,
+// so that lower priority behaviours only get the force left by the higher priority ones.
+type Priority []Weighted
+
+// Steer returns the prioritized steering force.
+func (p Priority) Steer(v Vehicle) vector.Vector2D {
+	result := vector.Vector2D{}
+	left := v.MaxForce()
+	for _, w := range p {
+		force := w.Steer(v)
+		force.Multiply(w.Weight)
+		magnitude := length(force)
+		if magnitude == 0 {
+			continue
+		}
+		force.Multiply(math.Min(magnitude, left) / magnitude)
+		result.Add(force)
+		left -= math.Min(magnitude, left)
+		if left <= 0 {
+			break
+		}
+	}
+	return result
+}
@@ -0,0 +1,85 @@
+package steering
+
+import (
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+// FollowLeader steers the vehicle to a point Behind pixels behind a leader.
+// Vehicles standing in front of the leader, closer than Sight pixels, get out of its way.
+type FollowLeader struct {
+	Leader       Mover
+	Behind       float64
+	ArriveRadius float64
+	Sight        float64
+}
+
+// Steer returns the leader following steering force.
+func (f FollowLeader) Steer(v Vehicle) vector.Vector2D {
+	direction := heading(f.Leader)
+	behind := direction
+	behind.Multiply(-f.Behind)
+	behind.Add(f.Leader.Position())
+	result := Arrive{Target: behind, Radius: f.ArriveRadius}.Steer(v)
+
+	ahead := direction
+	ahead.Multiply(f.Sight)
+	ahead.Add(f.Leader.Position())
+	if f.Sight > 0 && !direction.IsNil() &&
+		(v.World().Distance(v.Position(), ahead) < f.Sight || v.World().Distance(v.Position(), f.Leader.Position()) < f.Sight) {
+		result.Add(Evade{Threat: f.Leader}.Steer(v))
+		result.Limit(v.MaxForce())
+	}
+	return result
+}
+
+// Contain steers the vehicle back inside the [Min, Max] rectangle,
+// when it gets closer to the rectangle edges than Margin.
+type Contain struct {
+	Min    vector.Vector2D
+	Max    vector.Vector2D
+	Margin float64
+}
+
+// Steer returns the containment steering force.
+func (c Contain) Steer(v Vehicle) vector.Vector2D {
+	desired := v.Velocity()
+	p := v.Position()
+	near := false
+	if p.X < c.Min.X+c.Margin {
+		desired.X, near = v.MaxSpeed(), true
+	} else if p.X > c.Max.X-c.Margin {
+		desired.X, near = -v.MaxSpeed(), true
+	}
+	if p.Y < c.Min.Y+c.Margin {
+		desired.Y, near = v.MaxSpeed(), true
+	} else if p.Y > c.Max.Y-c.Margin {
+		desired.Y, near = -v.MaxSpeed(), true
+	}
+	if !near {
+		return vector.Vector2D{}
+	}
+	return towards(v, desired, v.MaxSpeed())
+}
+
+// Queue brakes the vehicle when a neighbour stands ahead of it, closer than Radius,
+// so that vehicles line up instead of crowding.
+type Queue struct {
+	Neighbours []Mover
+	Radius     float64
+}
+
+// Steer returns the queueing steering force.
+func (q Queue) Steer(v Vehicle) vector.Vector2D {
+	direction := heading(v)
+	if direction.IsNil() {
+		return vector.Vector2D{}
+	}
+	for _, n := range q.Neighbours {
+		delta := v.World().Delta(v.Position(), n.Position())
+		if delta.IsNil() || delta.MagnitudeSquared() >= q.Radius*q.Radius || dot(delta, direction) <= 0 {
+			continue
+		}
+		return brake(v)
+	}
+	return vector.Vector2D{}
+}
@@ -0,0 +1,73 @@
+package steering
+
+import (
+	"math"
+
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+// Path is a polyline of Radius pixels width.
+// A looping path connects its last point back to the first one.
+type Path struct {
+	Points []vector.Vector2D
+	Radius float64
+	Loop   bool
+}
+
+// FollowPath steers the vehicle along a path.
+// The vehicle predicts its position Lookahead pixels ahead, and when this position
+// leaves the path, it seeks the path Lookahead pixels further along it.
+type FollowPath struct {
+	Path      *Path
+	Lookahead float64
+}
+
+// Steer returns the path following steering force.
+// Geometry is computed relatively to the vehicle, so that paths may cross the world edges.
+func (f FollowPath) Steer(v Vehicle) vector.Vector2D {
+	points := f.Path.Points
+	segments := len(points) - 1
+	if f.Path.Loop {
+		segments = len(points)
+	}
+	future := heading(v)
+	future.Multiply(f.Lookahead)
+
+	var target vector.Vector2D
+	closest := math.Inf(1)
+	for i := 0; i < segments; i++ {
+		a := v.World().Delta(v.Position(), points[i])
+		b := v.World().Delta(v.Position(), points[(i+1)%len(points)])
+		normal, direction := project(future, a, b)
+		d := length(vector.Vector2D{X: future.X - normal.X, Y: future.Y - normal.Y})
+		if d >= closest {
+			continue
+		}
+		closest = d
+		target = direction
+		target.Multiply(f.Lookahead)
+		target.Add(normal)
+	}
+	if closest <= f.Path.Radius {
+		return vector.Vector2D{}
+	}
+	return towards(v, target, v.MaxSpeed())
+}
+
+// project returns the orthogonal projection of p on the segment [a, b], and the segment unit direction.
+func project(p, a, b vector.Vector2D) (vector.Vector2D, vector.Vector2D) {
+	direction := b
+	direction.Subtract(a)
+	if direction.IsNil() {
+		return a, direction
+	}
+	l := length(direction)
+	direction.Divide(l)
+	ap := p
+	ap.Subtract(a)
+	t := math.Max(0, math.Min(l, dot(ap, direction)))
+	normal := direction
+	normal.Multiply(t)
+	normal.Add(a)
+	return normal, direction
+}
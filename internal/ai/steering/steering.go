@@ -0,0 +1,172 @@
+// Package steering implements Craig Reynolds' steering behaviours.
+// Each behaviour returns a steering force for a Vehicle, and behaviours
+// are merged together with a Blend or a Priority combiner.
+package steering
+
+import (
+	"math"
+
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+// Mover is anything which has a position and a velocity, such as a physics.Physic.
+type Mover interface {
+	Position() vector.Vector2D
+	Velocity() vector.Vector2D
+}
+
+// Vehicle is an agent driven by steering behaviours.
+type Vehicle interface {
+	Mover
+	// World returns the topology in which the vehicle moves.
+	World() topology.Topology
+	// MaxSpeed returns the maximum vehicle velocity (in pixels per 1/60 [s]).
+	MaxSpeed() float64
+	// MaxForce returns the maximum steering force of the vehicle.
+	MaxForce() float64
+}
+
+// Behaviour computes a steering force for a vehicle.
+type Behaviour interface {
+	Steer(v Vehicle) vector.Vector2D
+}
+
+// BehaviourFunc is a function used as a Behaviour.
+type BehaviourFunc func(v Vehicle) vector.Vector2D
+
+// Steer calls f(v).
+func (f BehaviourFunc) Steer(v Vehicle) vector.Vector2D {
+	return f(v)
+}
+
+// Seek steers the vehicle towards a target, at full speed.
+type Seek struct {
+	Target vector.Vector2D
+}
+
+// Steer returns the seek steering force.
+func (s Seek) Steer(v Vehicle) vector.Vector2D {
+	return towards(v, v.World().Delta(v.Position(), s.Target), v.MaxSpeed())
+}
+
+// Flee steers the vehicle away from a target, at full speed.
+// The target is ignored beyond Radius, a zero Radius flees the target from anywhere.
+type Flee struct {
+	Target vector.Vector2D
+	Radius float64
+}
+
+// Steer returns the flee steering force.
+func (f Flee) Steer(v Vehicle) vector.Vector2D {
+	away := v.World().Delta(f.Target, v.Position())
+	if f.Radius > 0 && away.MagnitudeSquared() >= f.Radius*f.Radius {
+		return vector.Vector2D{}
+	}
+	return towards(v, away, v.MaxSpeed())
+}
+
+// Arrive steers the vehicle towards a target, and slows it down within Radius,
+// so that it stops on the target.
+type Arrive struct {
+	Target vector.Vector2D
+	Radius float64
+}
+
+// Steer returns the arrive steering force.
+func (a Arrive) Steer(v Vehicle) vector.Vector2D {
+	desired := v.World().Delta(v.Position(), a.Target)
+	if desired.IsNil() {
+		return brake(v)
+	}
+	speed := v.MaxSpeed()
+	if d := length(desired); d < a.Radius {
+		speed *= d / a.Radius
+	}
+	return towards(v, desired, speed)
+}
+
+// Pursue steers the vehicle towards the future position of a moving target.
+// The target move is anticipated for the time the vehicle needs to reach it,
+// up to MaxPrediction (in 1/60 [s]). The vehicle slows down within ArriveRadius.
+type Pursue struct {
+	Target        Mover
+	MaxPrediction float64
+	ArriveRadius  float64
+}
+
+// Steer returns the pursuit steering force.
+func (p Pursue) Steer(v Vehicle) vector.Vector2D {
+	return Arrive{
+		Target: predict(v, p.Target, p.MaxPrediction),
+		Radius: p.ArriveRadius,
+	}.Steer(v)
+}
+
+// Evade steers the vehicle away from the future position of a moving threat.
+// The threat is ignored beyond Radius, a zero Radius evades the threat from anywhere.
+type Evade struct {
+	Threat        Mover
+	MaxPrediction float64
+	Radius        float64
+}
+
+// Steer returns the evasion steering force.
+func (e Evade) Steer(v Vehicle) vector.Vector2D {
+	if e.Radius > 0 && v.World().Distance(v.Position(), e.Threat.Position()) >= e.Radius {
+		return vector.Vector2D{}
+	}
+	return Flee{
+		Target: predict(v, e.Threat, e.MaxPrediction),
+	}.Steer(v)
+}
+
+// predict returns the position of target, anticipated for the time the vehicle needs to reach it.
+func predict(v Vehicle, target Mover, maxPrediction float64) vector.Vector2D {
+	prediction := maxPrediction
+	if d := v.World().Distance(v.Position(), target.Position()); v.MaxSpeed() > 0 && d/v.MaxSpeed() < prediction {
+		prediction = d / v.MaxSpeed()
+	}
+	result := target.Velocity()
+	result.Multiply(prediction)
+	result.Add(target.Position())
+	return result
+}
+
+// towards returns the force steering the vehicle velocity to the desired direction, at a given speed.
+func towards(v Vehicle, desired vector.Vector2D, speed float64) vector.Vector2D {
+	if desired.IsNil() {
+		return vector.Vector2D{}
+	}
+	desired.Normalize()
+	desired.Multiply(speed)
+	desired.Subtract(v.Velocity())
+	desired.Limit(v.MaxForce())
+	return desired
+}
+
+// brake returns the force stopping the vehicle.
+func brake(v Vehicle) vector.Vector2D {
+	result := v.Velocity()
+	result.Multiply(-1)
+	result.Limit(v.MaxForce())
+	return result
+}
+
+// heading returns the unit vector of the vehicle velocity, or nil if the vehicle does not move.
+func heading(v Mover) vector.Vector2D {
+	h := v.Velocity()
+	if h.IsNil() {
+		return h
+	}
+	h.Normalize()
+	return h
+}
+
+func length(v vector.Vector2D) float64 {
+	return math.Sqrt(v.MagnitudeSquared())
+}
+
+func dot(a, b vector.Vector2D) float64 {
+	return a.X*b.X + a.Y*b.Y
+}
@@ -0,0 +1,162 @@
+package steering_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/ai/steering"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+type vehicle struct {
+	position vector.Vector2D
+	velocity vector.Vector2D
+	world    topology.Topology
+}
+
+func (v vehicle) Position() vector.Vector2D { return v.position }
+func (v vehicle) Velocity() vector.Vector2D { return v.velocity }
+func (v vehicle) World() topology.Topology  { return v.world }
+func (v vehicle) MaxSpeed() float64         { return 3 }
+func (v vehicle) MaxForce() float64         { return 0.3 }
+
+func TestBehaviours(t *testing.T) {
+	origin := vector.Vector2D{X: 100, Y: 100}
+	still := vehicle{position: origin, world: topology.Infinite{}}
+	moving := vehicle{position: origin, velocity: vector.Vector2D{X: 3}, world: topology.Infinite{}}
+
+	tests := []struct {
+		name      string
+		vehicle   vehicle
+		behaviour steering.Behaviour
+		// check returns true if the steering force points the expected way
+		check func(f vector.Vector2D) bool
+	}{
+		{
+			name:      "seek",
+			vehicle:   still,
+			behaviour: steering.Seek{Target: vector.Vector2D{X: 200, Y: 100}},
+			check:     func(f vector.Vector2D) bool { return f.X > 0 && f.Y == 0 },
+		},
+		{
+			name:      "seek across the edge",
+			vehicle:   vehicle{position: vector.Vector2D{X: 790, Y: 100}, world: topology.Toroidal{Width: 800, Height: 600}},
+			behaviour: steering.Seek{Target: vector.Vector2D{X: 10, Y: 100}},
+			check:     func(f vector.Vector2D) bool { return f.X > 0 },
+		},
+		{
+			name:      "flee",
+			vehicle:   still,
+			behaviour: steering.Flee{Target: vector.Vector2D{X: 100, Y: 50}},
+			check:     func(f vector.Vector2D) bool { return f.Y > 0 },
+		},
+		{
+			name:      "flee out of radius",
+			vehicle:   still,
+			behaviour: steering.Flee{Target: vector.Vector2D{X: 100, Y: 50}, Radius: 20},
+			check:     func(f vector.Vector2D) bool { return f.IsNil() },
+		},
+		{
+			name:      "arrive on target",
+			vehicle:   moving,
+			behaviour: steering.Arrive{Target: origin, Radius: 20},
+			check:     func(f vector.Vector2D) bool { return f.X < 0 },
+		},
+		{
+			name:      "pursue",
+			vehicle:   still,
+			behaviour: steering.Pursue{Target: vehicle{position: vector.Vector2D{X: 100, Y: 0}, velocity: vector.Vector2D{X: 3}}, MaxPrediction: 30},
+			check:     func(f vector.Vector2D) bool { return f.X > 0 && f.Y < 0 },
+		},
+		{
+			name:      "evade",
+			vehicle:   still,
+			behaviour: steering.Evade{Threat: vehicle{position: vector.Vector2D{X: 100, Y: 0}, velocity: vector.Vector2D{Y: 3}}, MaxPrediction: 30},
+			check:     func(f vector.Vector2D) bool { return f.Y > 0 },
+		},
+		{
+			name:      "wander",
+			vehicle:   moving,
+			behaviour: &steering.Wander{Rand: rand.New(rand.NewSource(1)), Distance: 30, Radius: 10, Jitter: 0.5},
+			check:     func(f vector.Vector2D) bool { return f.MagnitudeSquared() <= 0.3*0.3+1e-9 },
+		},
+		{
+			name:    "follow path",
+			vehicle: moving,
+			behaviour: steering.FollowPath{
+				Path:      &steering.Path{Points: []vector.Vector2D{{X: 0, Y: 200}, {X: 400, Y: 200}}, Radius: 10},
+				Lookahead: 20,
+			},
+			check: func(f vector.Vector2D) bool { return f.Y > 0 },
+		},
+		{
+			name:    "on path",
+			vehicle: moving,
+			behaviour: steering.FollowPath{
+				Path:      &steering.Path{Points: []vector.Vector2D{{X: 0, Y: 100}, {X: 400, Y: 100}}, Radius: 10},
+				Lookahead: 20,
+			},
+			check: func(f vector.Vector2D) bool { return f.IsNil() },
+		},
+		{
+			name:      "follow leader",
+			vehicle:   still,
+			behaviour: steering.FollowLeader{Leader: vehicle{position: vector.Vector2D{X: 200, Y: 100}, velocity: vector.Vector2D{X: 3}}, Behind: 30, Sight: 20},
+			check:     func(f vector.Vector2D) bool { return f.X > 0 },
+		},
+		{
+			name:      "out of the leader way",
+			vehicle:   still,
+			behaviour: steering.FollowLeader{Leader: vehicle{position: vector.Vector2D{X: 90, Y: 95}, velocity: vector.Vector2D{X: 3}}, Behind: 30, Sight: 20},
+			check:     func(f vector.Vector2D) bool { return f.Y > 0 },
+		},
+		{
+			name:      "contain",
+			vehicle:   vehicle{position: vector.Vector2D{X: 790, Y: 300}, velocity: vector.Vector2D{X: 3}, world: topology.Infinite{}},
+			behaviour: steering.Contain{Max: vector.Vector2D{X: 800, Y: 600}, Margin: 50},
+			check:     func(f vector.Vector2D) bool { return f.X < 0 },
+		},
+		{
+			name:      "queue",
+			vehicle:   moving,
+			behaviour: steering.Queue{Neighbours: []steering.Mover{vehicle{position: vector.Vector2D{X: 110, Y: 100}}}, Radius: 20},
+			check:     func(f vector.Vector2D) bool { return f.X < 0 },
+		},
+		{
+			name:      "queue behind",
+			vehicle:   moving,
+			behaviour: steering.Queue{Neighbours: []steering.Mover{vehicle{position: vector.Vector2D{X: 90, Y: 100}}}, Radius: 20},
+			check:     func(f vector.Vector2D) bool { return f.IsNil() },
+		},
+		{
+			name:    "blend",
+			vehicle: still,
+			behaviour: steering.Blend{
+				{Behaviour: steering.Seek{Target: vector.Vector2D{X: 200, Y: 100}}, Weight: 1},
+				{Behaviour: steering.Seek{Target: vector.Vector2D{X: 100, Y: 200}}, Weight: 1},
+			},
+			check: func(f vector.Vector2D) bool { return f.X > 0 && f.Y > 0 && f.MagnitudeSquared() <= 0.3*0.3+1e-9 },
+		},
+		{
+			name:    "priority",
+			vehicle: still,
+			behaviour: steering.Priority{
+				{Behaviour: steering.Seek{Target: vector.Vector2D{X: 200, Y: 100}}, Weight: 1},
+				{Behaviour: steering.Seek{Target: vector.Vector2D{X: 100, Y: 200}}, Weight: 1},
+			},
+			check: func(f vector.Vector2D) bool { return f.X > 0 && f.Y == 0 },
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			f := tt.behaviour.Steer(tt.vehicle)
+			if !tt.check(f) {
+				t.Errorf("unexpected steering force %v", f)
+			}
+		})
+	}
+}
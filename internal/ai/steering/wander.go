@@ -0,0 +1,34 @@
+package steering
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+// Wander steers the vehicle randomly, yet smoothly.
+// The vehicle seeks a target moving on a circle of Radius, projected Distance pixels ahead of it.
+// Every step, the target moves on the circle by a random angle up to Jitter (radian).
+type Wander struct {
+	Rand     *rand.Rand
+	Distance float64
+	Radius   float64
+	Jitter   float64
+	angle    float64
+}
+
+// Steer returns the wander steering force.
+func (w *Wander) Steer(v Vehicle) vector.Vector2D {
+	w.angle += (2*w.Rand.Float64() - 1) * w.Jitter
+	target := heading(v)
+	if target.IsNil() {
+		target = vector.Vector2D{X: 1}
+	}
+	target.Multiply(w.Distance)
+	target.Add(vector.Vector2D{
+		X: w.Radius * math.Cos(w.angle),
+		Y: w.Radius * math.Sin(w.angle),
+	})
+	return towards(v, target, v.MaxSpeed())
+}
@@ -0,0 +1,59 @@
+package clock
+
+import "time"
+
+const (
+	defaultTPS int = 60
+)
+
+// Clock measures the game time by counting ticks.
+// Game time does not depend on wall clock, so that a game can be replayed identically.
+type Clock struct {
+	ticks int64
+	tps   int
+}
+
+// New creates a clock which advances by 1/tps second every tick.
+func New(tps int) *Clock {
+	if tps <= 0 {
+		tps = defaultTPS
+	}
+	return &Clock{
+		tps: tps,
+	}
+}
+
+// Tick advances the clock by one tick.
+func (c *Clock) Tick() {
+	c.ticks++
+}
+
+// Ticks returns the number of ticks elapsed since the clock was reset.
+func (c *Clock) Ticks() int64 {
+	return c.ticks
+}
+
+// TPS returns the number of ticks per second.
+func (c *Clock) TPS() int {
+	return c.tps
+}
+
+// Step returns the duration of a tick, in seconds.
+func (c *Clock) Step() float64 {
+	return 1 / float64(c.tps)
+}
+
+// Now returns the game time elapsed since the clock was reset.
+func (c *Clock) Now() time.Duration {
+	return time.Duration(c.ticks) * time.Second / time.Duration(c.tps)
+}
+
+// Since returns the game time elapsed since t.
+func (c *Clock) Since(t time.Duration) time.Duration {
+	return c.Now() - t
+}
+
+// Reset sets the clock back to zero.
+func (c *Clock) Reset() {
+	c.ticks = 0
+}
@@ -1,6 +1,32 @@
 package config
 
-import "github.com/jtbonhomme/conf"
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jtbonhomme/conf"
+	"github.com/segmentio/objconv/yaml"
+)
+
+// Commands supported by the asteboids executable.
+const (
+	PlayCommand   string = "play"
+	SimCommand    string = "sim"
+	EvolveCommand string = "evolve"
+	TrainCommand  string = "train"
+)
+
+// Win conditions of a level.
+const (
+	// ClearWin is met once all asteroids and rubble are destroyed.
+	ClearWin string = "clear"
+	// ScoreWin is met once goal points are won during the level.
+	ScoreWin string = "score"
+	// SurviveWin is met once the starship survived goal seconds.
+	SurviveWin string = "survive"
+)
 
 const (
 	defaultAsteroids        int     = 4
@@ -10,23 +36,228 @@ const (
 	defaultScoreTimeUnit    float64 = 5
 	defaultAsteroidsRespawn float64 = 10
 	defaultMaxTPS           int     = 60
+	defaultPhysicsTPS       int     = 60
+	defaultIntegrator       string  = "euler"
+	defaultRestitution      float64 = 1
 	defaultVisionRadius     float64 = 75
 	defaultMute             bool    = true
+	defaultTicks            int     = 10000
+	defaultTopology         string  = "toroidal"
+	defaultBoidMaxSpeed     float64 = 3.0
+	defaultBoidMaxForce     float64 = 0.3
+	defaultSeparationWeight float64 = 1.9
+	defaultCohesionWeight   float64 = 1.5
+	defaultAlignmentWeight  float64 = 1.3
+	defaultFieldOfView      float64 = 360
+	defaultAvoidanceRadius  float64 = 60
+	defaultAvoidanceWeight  float64 = 2
+	defaultFleeRadius       float64 = 50
+	defaultFleeWeight       float64 = 3
+	defaultRepulsionWeight  float64 = 3
+	defaultWallsRadius      float64 = 50
+	defaultWallsWeight      float64 = 2
+	defaultPredators        int     = 0
+	defaultPredatorSpeed    float64 = 3.2
+	defaultPredatorForce    float64 = 0.15
+	defaultHuntRadius       float64 = 250
+	defaultHuntWeight       float64 = 1.5
+	defaultArriveRadius     float64 = 20
+	defaultMaxPrediction    float64 = 30
+	defaultSpeciesName      string  = "boid"
+	defaultSpeciesColor     string  = "#6464c8"
+	defaultSpeciesSize      float64 = 10
+	defaultBoidScore        int     = 1
+	defaultBoidsRespawn     float64 = 5
+	defaultPanicRadius      float64 = 100
+	defaultPanicForce       float64 = 1
+	defaultPanicDecay       float64 = 2
+	defaultMetricsEvery     int     = 60
+	defaultMetricsFormat    string  = "csv"
+	defaultPopulation       int     = 20
+	defaultGenerations      int     = 10
+	defaultEvolveTicks      int     = 3600
+	defaultFitness          string  = "survival"
+	defaultMutationRate     float64 = 0.1
+	defaultElite            int     = 2
+	defaultBest             int     = 3
+	defaultEvolveOutput     string  = "evolved"
+	defaultNeuralColor      string  = "#64c864"
+	defaultLives            int     = 3
+	defaultExtraLife        int     = 100
+	defaultInvulnerability  float64 = 3
+	defaultSaucerEvery      float64 = 20
+	defaultSmallSaucers     float64 = 0.3
+	defaultBigSaucerScore   int     = 20
+	defaultSmallSaucerScore int     = 100
+	defaultHiddenNeurons    int     = 8
+	defaultAsteroidSplit    int     = 2
+	defaultAsteroidSpread   float64 = 1
+	defaultLargeScore       int     = 2
+	defaultMediumScore      int     = 5
+	defaultSmallScore       int     = 10
+	defaultJumpCooldown     float64 = 5
+	defaultJumpFailure      float64 = 0.1
+	defaultShieldDrain      float64 = 0.5
+	defaultShieldRecharge   float64 = 0.1
 )
 
+// FlockingRule configures a boids flocking rule.
+type FlockingRule struct {
+	Radius float64 `conf:"radius" help:"Distance (in pixels) within which neighbours are considered by the rule (default is 75)."`
+	Weight float64 `conf:"weight" help:"Factor of the rule steering force in the boid acceleration."`
+}
+
+// Flocking configures the boids flocking behaviour.
+type Flocking struct {
+	MaxSpeed    float64      `conf:"maxSpeed" help:"Maximum boid velocity (in pixels per 1/60 second, default is 3)."`
+	MaxForce    float64      `conf:"maxForce" help:"Maximum steering force of each flocking rule (default is 0.3)."`
+	Separation  FlockingRule `conf:"separation" help:"Separation rule, to avoid crowding neighbours (default weight is 1.9)."`
+	Cohesion    FlockingRule `conf:"cohesion" help:"Cohesion rule, to steer towards the center of neighbours (default weight is 1.5)."`
+	Alignment   FlockingRule `conf:"alignment" help:"Alignment rule, to steer towards the heading of neighbours (default weight is 1.3)."`
+	Avoidance   FlockingRule `conf:"avoidance" help:"Asteroids avoidance, the radius is the look-ahead distance (default radius is 60, weight is 2)."`
+	Flee        FlockingRule `conf:"flee" help:"Bullets avoidance, the radius is the threat distance (default radius is 50, weight is 3)."`
+	Repulsion   FlockingRule `conf:"repulsion" help:"Avoidance of the boids of disliked species, with a negative affinity (default radius is 75, weight is 3)."`
+	Walls       FlockingRule `conf:"walls" help:"Walls avoidance in a bounded world, the radius is the distance to walls (default radius is 50, weight is 2)."`
+	FieldOfView float64      `conf:"fieldOfView" help:"Angle (in degrees) of the boids view cone, centered on their heading (default is 360)."`
+	Occlusion   bool         `conf:"occlusion" help:"Asteroids hide the agents behind them from boids (default is false)."`
+}
+
+// Species configures a boids species.
+type Species struct {
+	Name     string             `conf:"name" help:"Name of the species (default is boid)."`
+	Boids    int                `conf:"boids" help:"Number of boids of the species at the start of the game."`
+	Color    string             `conf:"color" help:"Color of the species boids, as #rrggbb (default is #6464c8)."`
+	Size     float64            `conf:"size" help:"Size (in pixels) of the species boids (default is 10)."`
+	Flocking Flocking           `conf:"flocking" help:"Flocking parameters of the species, unset ones are read from the flocking section."`
+	Affinity map[string]float64 `conf:"affinity" help:"Affinity of the species towards other species, from -1 (flee them) to 1 (flock with them, default is 0)."`
+}
+
+// Panic configures the boids reaction to the death of a neighbour.
+type Panic struct {
+	Radius float64 `conf:"radius" help:"Distance (in pixels) within which boids panic when a boid dies (default is 100)."`
+	Force  float64 `conf:"force" help:"Initial panic steering force, away from the dead boid (default is 1)."`
+	Decay  float64 `conf:"decay" help:"Ratio of the panic force lost per second (default is 2)."`
+}
+
+// Level configures a wave of the game, which ends when its win condition is met.
+type Level struct {
+	Name          string  `conf:"name" help:"Name of the level, shown before it starts (default is empty)."`
+	Asteroids     int     `conf:"asteroids" help:"Number of asteroids of the wave."`
+	AsteroidSpeed float64 `conf:"asteroidSpeed" help:"Speed of the asteroids (in pixels per 1/60 second, default is a random speed between 0.6 and 0.8)."`
+	Boids         int     `conf:"boids" help:"Number of boids of each species during the level (default is the species number)."`
+	SaucerEvery   float64 `conf:"saucerEvery" help:"Average time delay (in second) between two flying saucers, 0 for no saucer (default is 0)."`
+	Win           string  `conf:"win" help:"Win condition: clear (destroy all asteroids), score (win goal points) or survive (for goal seconds), default is clear."`
+	Goal          float64 `conf:"goal" help:"Points or seconds of the score and survive win conditions."`
+}
+
+// Asteroid configures the asteroids size tiers: large asteroids split into medium ones,
+// medium ones into small ones, which disappear when hit.
+type Asteroid struct {
+	Split       int     `conf:"split" help:"Number of smaller asteroids an asteroid splits into, 0 to never split (default is 2)."`
+	Spread      float64 `conf:"spread" help:"Speed (in pixels per 1/60 second) at which split asteroids move apart (default is 1)."`
+	LargeScore  int     `conf:"largeScore" help:"Points won for every large asteroid destroyed (default is 2)."`
+	MediumScore int     `conf:"mediumScore" help:"Points won for every medium asteroid destroyed (default is 5)."`
+	SmallScore  int     `conf:"smallScore" help:"Points won for every small asteroid destroyed (default is 10)."`
+}
+
+// Hyperspace configures the starship jump to a random position.
+type Hyperspace struct {
+	Cooldown float64 `conf:"cooldown" help:"Time (in second) between two hyperspace jumps (default is 5)."`
+	Failure  float64 `conf:"failure" help:"Probability for the starship to self destroy when it jumps (default is 0.1)."`
+}
+
+// Shield configures the starship shield, which deflects asteroids.
+type Shield struct {
+	Drain    float64 `conf:"drain" help:"Energy drained per second while the shield is up, from a full energy of 1 (default is 0.5)."`
+	Recharge float64 `conf:"recharge" help:"Energy recharged per second while the shield is down (default is 0.1)."`
+}
+
+// Saucer configures the flying saucers crossing the screen and shooting at the starship.
+type Saucer struct {
+	Every      float64 `conf:"every" help:"Average time delay (in second) between two flying saucers, 0 for no saucer (endless mode only, levels set their own, default is 20)."`
+	Small      float64 `conf:"small" help:"Probability for a flying saucer to be a small one, which aims better (default is 0.3)."`
+	BigScore   int     `conf:"bigScore" help:"Points won for every big saucer shot (default is 20)."`
+	SmallScore int     `conf:"smallScore" help:"Points won for every small saucer shot (default is 100)."`
+}
+
+// Metrics configures the flocking metrics.
+type Metrics struct {
+	Every  int    `conf:"every" help:"Physics steps between two measures of the flocking metrics, 0 to never measure them (default is 60)."`
+	File   string `conf:"file" help:"File the flocking metrics are exported to (default is empty, for no export)."`
+	Format string `conf:"format" help:"Format of the exported flocking metrics: csv or json (default is csv)."`
+}
+
+// Evolve configures the genetic algorithm evolving the boids flocking parameters, or the neural boids networks.
+type Evolve struct {
+	Population   int     `conf:"population" help:"Number of genomes per generation (default is 20)."`
+	Generations  int     `conf:"generations" help:"Number of generations (default is 10)."`
+	Ticks        int     `conf:"ticks" help:"Physics steps simulated to evaluate a genome (default is 3600)."`
+	Fitness      string  `conf:"fitness" help:"Fitness of genomes: survival, compactness or collisions (default is survival)."`
+	MutationRate float64 `conf:"mutationRate" help:"Probability of a gene mutation (default is 0.1)."`
+	Elite        int     `conf:"elite" help:"Number of best genomes kept unchanged in the next generation (default is 2)."`
+	Best         int     `conf:"best" help:"Number of best genomes written out (default is 3)."`
+	Output       string  `conf:"output" help:"Directory the best genomes are written to, as config files or networks (default is evolved)."`
+}
+
+// Neural configures the boids driven by a neural network, and the training of networks.
+type Neural struct {
+	Boids  int    `conf:"boids" help:"Number of neural boids at the start of the game (default is 0)."`
+	Brain  string `conf:"brain" help:"JSON file of the neural network driving neural boids, as written by the train command (default is empty)."`
+	Color  string `conf:"color" help:"Color of the neural boids, as #rrggbb (default is #64c864)."`
+	Hidden int    `conf:"hidden" help:"Number of hidden neurons of the networks trained by the train command (default is 8)."`
+}
+
+// Predator configures the predators hunting the starship.
+type Predator struct {
+	MaxSpeed      float64 `conf:"maxSpeed" help:"Maximum predator velocity (in pixels per 1/60 second, default is 3.2)."`
+	MaxForce      float64 `conf:"maxForce" help:"Maximum steering force of predators (default is 0.15)."`
+	HuntRadius    float64 `conf:"huntRadius" help:"Distance (in pixels) within which predators see the starship (default is 250)."`
+	Weight        float64 `conf:"weight" help:"Factor of the pursuit steering force in the predator acceleration (default is 1.5)."`
+	ArriveRadius  float64 `conf:"arriveRadius" help:"Distance (in pixels) from the starship below which predators slow down (default is 20)."`
+	MaxPrediction float64 `conf:"maxPrediction" help:"Maximum time (in 1/60 second) predators anticipate the starship move (default is 30)."`
+}
+
 type Config struct {
-	Mute             bool    `conf:"mute" help:"Mute sound (default is true)."`
-	Debug            bool    `conf:"debug" help:"Debug log level activated (default is false)."`
-	Optim            bool    `conf:"optim" help:"Optimized mode activated (default is false)."`
-	CPUProfile       string  `conf:"cpuprofile" help:"Write CPU profile to file (default is empty)."`
-	Asteroids        int     `conf:"asteroids" help:"Number of asteroids at the start of the game (default is 4)."`
-	Boids            int     `conf:"boids" help:"Number of boids at the start of the game (default is 60)."`
-	ScreenWidth      float64 `conf:"screenWidth" help:"Screen width (in pixels, default is 1080)."`
-	ScreenHeight     float64 `conf:"screenHeight" help:"Screen height (in pixels, default is 720)."`
-	ScoreTimeUnit    float64 `conf:"scoreTimeUnit" help:"Time delay (in second) to win one point (default is 5)."`
-	AsteroidsRespawn float64 `conf:"asteroidsRespawn" help:"Time delay (in second) before a new asteroids spawn (default is 10)."`
-	MaxTPS           int     `conf:"maxTPS" help:"Maximum ticks per second  (default is 60)."`
-	VisionRadius     float64 `conf:"visionRadius" help:"Radius (in pixels) of the agents vision (default is 150)."`
+	Command          string     `conf:"-"`
+	Mute             bool       `conf:"mute" help:"Mute sound (default is true)."`
+	Debug            bool       `conf:"debug" help:"Debug log level activated (default is false)."`
+	Optim            bool       `conf:"optim" help:"Optimized mode activated (default is false)."`
+	CPUProfile       string     `conf:"cpuprofile" help:"Write CPU profile to file (default is empty)."`
+	Asteroids        int        `conf:"asteroids" help:"Number of asteroids at the start of the game (endless mode only, levels set their own, default is 4)."`
+	Boids            int        `conf:"boids" help:"Number of boids at the start of the game (default is 60)."`
+	Predators        int        `conf:"predators" help:"Number of predators hunting the starship at the start of the game, 0 for none (default is 0)."`
+	ScreenWidth      float64    `conf:"screenWidth" help:"Screen width (in pixels, default is 1080)."`
+	ScreenHeight     float64    `conf:"screenHeight" help:"Screen height (in pixels, default is 720)."`
+	ScoreTimeUnit    float64    `conf:"scoreTimeUnit" help:"Time delay (in second) to win one point (default is 5)."`
+	Lives            int        `conf:"lives" help:"Number of starships at the start of the game, the first one included (default is 3)."`
+	ExtraLife        int        `conf:"extraLife" help:"Points to win an extra life, 0 to never win one (default is 100)."`
+	Invulnerability  float64    `conf:"invulnerability" help:"Time (in second) a respawned starship can not be hit (default is 3)."`
+	Hyperspace       Hyperspace `conf:"hyperspace" help:"Starship hyperspace jump."`
+	Shield           Shield     `conf:"shield" help:"Starship shield."`
+	Asteroid         Asteroid   `conf:"asteroid" help:"Asteroids splitting and scores."`
+	AsteroidsRespawn float64    `conf:"asteroidsRespawn" help:"Time delay (in second) before a new asteroids spawn (endless mode only, default is 10)."`
+	MaxTPS           int        `conf:"maxTPS" help:"Maximum ticks per second  (default is 60)."`
+	PhysicsTPS       int        `conf:"physicsTPS" help:"Physics steps per second, independent from maxTPS (default is 60)."`
+	Integrator       string     `conf:"integrator" help:"Physics integrator: euler (semi-implicit) or verlet (default is euler)."`
+	VisionRadius     float64    `conf:"visionRadius" help:"Radius (in pixels) of the agents vision (default is 150)."`
+	Flocking         Flocking   `conf:"flocking" help:"Flocking parameters of boids."`
+	Species          []Species  `conf:"species" help:"Boids species, replacing the boids option (default is a single species)."`
+	BoidScore        int        `conf:"boidScore" help:"Points won for every boid shot (default is 1)."`
+	BoidsRespawn     float64    `conf:"boidsRespawn" help:"Time delay (in second) between two respawns of the boids killed, which refill each species, 0 to never respawn them (default is 5)."`
+	Panic            Panic      `conf:"panic" help:"Reaction of boids to the death of a neighbour."`
+	Saucer           Saucer     `conf:"saucer" help:"Flying saucers shooting at the starship."`
+	Levels           []Level    `conf:"levels" help:"Levels of the game, played in order until the last one is won (default is 5 built-in levels)."`
+	Endless          bool       `conf:"endless" help:"Play an endless game, without levels: asteroids respawn and the game goes on until the starship is lost (default is false)."`
+	Metrics          Metrics    `conf:"metrics" help:"Flocking metrics measures and export."`
+	Evolve           Evolve     `conf:"evolve" help:"Genetic algorithm run by the evolve and train commands."`
+	Neural           Neural     `conf:"neural" help:"Boids driven by a neural network."`
+	Predator         Predator   `conf:"predator" help:"Hunting parameters of predators."`
+	Collisions       []string   `conf:"collisions" help:"Collision rules, as <type A>:<type B>:<handler> (handlers are explode, explodeBoth, shot, bounce and deflect)."`
+	Restitution      float64    `conf:"restitution" help:"Restitution of bounces, from 0 (inelastic) to 1 (elastic, default)."`
+	Topology         string     `conf:"topology" help:"Shape of the world: toroidal, bounded or infinite (default is toroidal)."`
+	Ticks            int        `conf:"ticks" help:"Maximum number of ticks run by the sim command, which stops once the game is over (default is 10000)."`
+	Seed             int64      `conf:"seed" help:"Seed of the game random generator, a same seed replays a same game (default is 0, for a seed based on the current time)."`
+	Realtime         bool       `conf:"realtime" help:"Pace the sim command at maxTPS instead of running as fast as possible (default is false)."`
 }
 
 func New() *Config {
@@ -34,13 +265,231 @@ func New() *Config {
 		Mute:             defaultMute,
 		Asteroids:        defaultAsteroids,
 		Boids:            defaultBoids,
+		Predators:        defaultPredators,
 		ScreenWidth:      defaultScreenWidth,
 		ScreenHeight:     defaultScreenHeight,
 		ScoreTimeUnit:    defaultScoreTimeUnit,
+		Lives:            defaultLives,
+		ExtraLife:        defaultExtraLife,
+		Invulnerability:  defaultInvulnerability,
+		Hyperspace:       DefaultHyperspace(),
+		Shield:           DefaultShield(),
+		Asteroid:         DefaultAsteroid(),
 		AsteroidsRespawn: defaultAsteroidsRespawn,
 		MaxTPS:           defaultMaxTPS,
+		PhysicsTPS:       defaultPhysicsTPS,
+		Integrator:       defaultIntegrator,
+		Restitution:      defaultRestitution,
 		VisionRadius:     defaultVisionRadius,
+		Topology:         defaultTopology,
+		Collisions:       DefaultCollisions(),
+		Flocking:         DefaultFlocking(),
+		Predator:         DefaultPredator(),
+		BoidScore:        defaultBoidScore,
+		BoidsRespawn:     defaultBoidsRespawn,
+		Panic:            DefaultPanic(),
+		Saucer:           DefaultSaucer(),
+		Levels:           DefaultLevels(),
+		Metrics: Metrics{
+			Every:  defaultMetricsEvery,
+			Format: defaultMetricsFormat,
+		},
+		Evolve: DefaultEvolve(),
+		Neural: Neural{
+			Color:  defaultNeuralColor,
+			Hidden: defaultHiddenNeurons,
+		},
+		Ticks: defaultTicks,
 	}
-	conf.Load(config)
+
+	name := filepath.Base(os.Args[0])
+	args := os.Args[1:]
+	// the play command is the default one and can be omitted
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		args = append([]string{PlayCommand}, args...)
+	}
+	config.Command, _ = conf.LoadWith(config, conf.Loader{
+		Name: name,
+		Args: args,
+		Commands: []conf.Command{
+			{Name: PlayCommand, Help: "Play asteboids in a window (default command)"},
+			{Name: SimCommand, Help: "Run a headless simulation of asteboids"},
+			{Name: EvolveCommand, Help: "Evolve the boids flocking parameters with a genetic algorithm"},
+			{Name: TrainCommand, Help: "Train the neural network of neural boids by neuroevolution"},
+		},
+		Sources: []conf.Source{
+			conf.NewFileSource("config-file", envVars(), ioutil.ReadFile, yaml.Unmarshal),
+			conf.NewEnvSource(name, os.Environ()...),
+		},
+	})
 	return config
 }
+
+// DefaultCollisions returns the collision rules of the original game:
+// the starship explodes on asteroids unless its shield deflects them, asteroids are shot by bullets and bounce off each other.
+func DefaultCollisions() []string {
+	return []string{
+		"starship:asteroid:deflect",
+		"starship:rubble:deflect",
+		"asteroid:bullet:shot",
+		"rubble:bullet:shot",
+		"asteroid:asteroid:bounce",
+		"asteroid:rubble:bounce",
+		"rubble:rubble:bounce",
+		"starship:predator:explode",
+		"predator:bullet:shot",
+		"boid:bullet:shot",
+		"starship:saucer:explode",
+		"starship:saucerBullet:explode",
+		"saucer:bullet:shot",
+		"saucer:asteroid:explode",
+		"saucer:rubble:explode",
+	}
+}
+
+// DefaultEvolve returns the default genetic algorithm parameters.
+func DefaultEvolve() Evolve {
+	return Evolve{
+		Population:   defaultPopulation,
+		Generations:  defaultGenerations,
+		Ticks:        defaultEvolveTicks,
+		Fitness:      defaultFitness,
+		MutationRate: defaultMutationRate,
+		Elite:        defaultElite,
+		Best:         defaultBest,
+		Output:       defaultEvolveOutput,
+	}
+}
+
+// DefaultPanic returns the default reaction of boids to the death of a neighbour.
+func DefaultPanic() Panic {
+	return Panic{
+		Radius: defaultPanicRadius,
+		Force:  defaultPanicForce,
+		Decay:  defaultPanicDecay,
+	}
+}
+
+// DefaultLevels returns the built-in levels: asteroids get more numerous and faster,
+// and saucers show up more often.
+func DefaultLevels() []Level {
+	return []Level{
+		{Name: "first contact", Asteroids: 4, AsteroidSpeed: 0.8, Win: ClearWin},
+		{Name: "the flock grows", Asteroids: 5, AsteroidSpeed: 1, Boids: 90, SaucerEvery: 30, Win: ClearWin},
+		{Name: "saucer season", Asteroids: 6, AsteroidSpeed: 1.1, SaucerEvery: 12, Win: SurviveWin, Goal: 60},
+		{Name: "meteor storm", Asteroids: 8, AsteroidSpeed: 1.3, SaucerEvery: 20, Win: ClearWin},
+		{Name: "last stand", Asteroids: 10, AsteroidSpeed: 1.5, Boids: 110, SaucerEvery: 15, Win: ClearWin},
+	}
+}
+
+// DefaultHyperspace returns the default starship hyperspace jump.
+func DefaultHyperspace() Hyperspace {
+	return Hyperspace{
+		Cooldown: defaultJumpCooldown,
+		Failure:  defaultJumpFailure,
+	}
+}
+
+// DefaultShield returns the default starship shield, up for 2 seconds and recharged in 10 seconds.
+func DefaultShield() Shield {
+	return Shield{
+		Drain:    defaultShieldDrain,
+		Recharge: defaultShieldRecharge,
+	}
+}
+
+// DefaultAsteroid returns the default asteroids splitting, as in the original game.
+func DefaultAsteroid() Asteroid {
+	return Asteroid{
+		Split:       defaultAsteroidSplit,
+		Spread:      defaultAsteroidSpread,
+		LargeScore:  defaultLargeScore,
+		MediumScore: defaultMediumScore,
+		SmallScore:  defaultSmallScore,
+	}
+}
+
+// DefaultSaucer returns the default flying saucers, as in the original game.
+func DefaultSaucer() Saucer {
+	return Saucer{
+		Every:      defaultSaucerEvery,
+		Small:      defaultSmallSaucers,
+		BigScore:   defaultBigSaucerScore,
+		SmallScore: defaultSmallSaucerScore,
+	}
+}
+
+// DefaultPredator returns the default hunting parameters of predators.
+func DefaultPredator() Predator {
+	return Predator{
+		MaxSpeed:      defaultPredatorSpeed,
+		MaxForce:      defaultPredatorForce,
+		HuntRadius:    defaultHuntRadius,
+		Weight:        defaultHuntWeight,
+		ArriveRadius:  defaultArriveRadius,
+		MaxPrediction: defaultMaxPrediction,
+	}
+}
+
+// DefaultFlocking returns the flocking parameters of the original boids.
+func DefaultFlocking() Flocking {
+	return Flocking{
+		MaxSpeed:    defaultBoidMaxSpeed,
+		MaxForce:    defaultBoidMaxForce,
+		Separation:  FlockingRule{Radius: defaultVisionRadius, Weight: defaultSeparationWeight},
+		Cohesion:    FlockingRule{Radius: defaultVisionRadius, Weight: defaultCohesionWeight},
+		Alignment:   FlockingRule{Radius: defaultVisionRadius, Weight: defaultAlignmentWeight},
+		Avoidance:   FlockingRule{Radius: defaultAvoidanceRadius, Weight: defaultAvoidanceWeight},
+		Flee:        FlockingRule{Radius: defaultFleeRadius, Weight: defaultFleeWeight},
+		Repulsion:   FlockingRule{Radius: defaultVisionRadius, Weight: defaultRepulsionWeight},
+		Walls:       FlockingRule{Radius: defaultWallsRadius, Weight: defaultWallsWeight},
+		FieldOfView: defaultFieldOfView,
+	}
+}
+
+// DefaultSpecies returns a species of boids, as many as given, flying with the default flocking parameters.
+func DefaultSpecies(boids int) Species {
+	return Species{
+		Name:  defaultSpeciesName,
+		Boids: boids,
+		Color: defaultSpeciesColor,
+		Size:  defaultSpeciesSize,
+	}
+}
+
+// Inherit returns the flocking parameters, whose unset (zero) values are read from base.
+func (f Flocking) Inherit(base Flocking) Flocking {
+	inherit := func(v, base float64) float64 {
+		if v == 0 {
+			return base
+		}
+		return v
+	}
+	rule := func(r, base FlockingRule) FlockingRule {
+		return FlockingRule{Radius: inherit(r.Radius, base.Radius), Weight: inherit(r.Weight, base.Weight)}
+	}
+	return Flocking{
+		MaxSpeed:    inherit(f.MaxSpeed, base.MaxSpeed),
+		MaxForce:    inherit(f.MaxForce, base.MaxForce),
+		Separation:  rule(f.Separation, base.Separation),
+		Cohesion:    rule(f.Cohesion, base.Cohesion),
+		Alignment:   rule(f.Alignment, base.Alignment),
+		Avoidance:   rule(f.Avoidance, base.Avoidance),
+		Flee:        rule(f.Flee, base.Flee),
+		Repulsion:   rule(f.Repulsion, base.Repulsion),
+		Walls:       rule(f.Walls, base.Walls),
+		FieldOfView: inherit(f.FieldOfView, base.FieldOfView),
+		Occlusion:   f.Occlusion || base.Occlusion,
+	}
+}
+
+// envVars returns environment variables, which can be referenced in the configuration file.
+func envVars() map[string]string {
+	vars := make(map[string]string)
+	for _, e := range os.Environ() {
+		if i := strings.IndexByte(e, '='); i >= 0 {
+			vars[e[:i]] = e[i+1:]
+		}
+	}
+	return vars
+}
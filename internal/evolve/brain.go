@@ -0,0 +1,90 @@
+package evolve
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+
+	"github.com/jtbonhomme/asteboids/internal/ai"
+	"github.com/jtbonhomme/asteboids/internal/ai/neural"
+	"github.com/jtbonhomme/asteboids/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Bounds and mutation noise of the network weights.
+const (
+	maxWeight   float64 = 5
+	weightSigma float64 = 0.3
+)
+
+// Train evolves conf.Evolve.Generations generations of networks driving neural boids,
+// and writes out the best ones as JSON files.
+// Training goes on from the configured brain, if any.
+func Train(log *logrus.Logger, conf *config.Config) error {
+	rng, err := start(log, conf, "Train neural boids")
+	if err != nil {
+		return err
+	}
+
+	layers := Layers(conf.Neural.Hidden)
+	population := []Genome{}
+	if conf.Neural.Brain != "" {
+		brain, err := neural.LoadFile(conf.Neural.Brain)
+		if err != nil {
+			return err
+		}
+		layers = brain.Layers
+		population = append(population, NewBrainGenome(brain))
+	}
+	if layers[0] != ai.Sensors || layers[len(layers)-1] != ai.Actions {
+		return fmt.Errorf("invalid network layers %v, expected %d inputs and %d outputs", layers, ai.Sensors, ai.Actions)
+	}
+	for len(population) < conf.Evolve.Population {
+		population = append(population, RandomBrainGenome(rng, layers))
+	}
+
+	population, err = search(log, conf, rng, population, func(log *logrus.Logger, g Genome, seed int64) (float64, error) {
+		return EvaluateBrain(log, conf, g.Network(layers), seed)
+	}, (*Genome).MutateWeights)
+	if err != nil {
+		return err
+	}
+	return writeBest(log, conf, population, "brain%d.json", func(w io.Writer, g Genome) error {
+		return g.Network(layers).Save(w)
+	})
+}
+
+// Layers returns the layers of a neural boid network, with a hidden layer of the given size, if any.
+func Layers(hidden int) []int {
+	if hidden <= 0 {
+		return []int{ai.Sensors, ai.Actions}
+	}
+	return []int{ai.Sensors, hidden, ai.Actions}
+}
+
+// NewBrainGenome creates a genome from the weights of a network.
+func NewBrainGenome(n *neural.Network) Genome {
+	return Genome{Genes: append([]float64{}, n.Weights...)}
+}
+
+// RandomBrainGenome creates a genome with the random weights of a network of given layers.
+func RandomBrainGenome(rng *rand.Rand, layers []int) Genome {
+	n := neural.New(layers...)
+	n.Randomize(rng)
+	return Genome{Genes: n.Weights}
+}
+
+// Network returns the network of given layers, whose weights are the genome genes.
+func (g Genome) Network(layers []int) *neural.Network {
+	return &neural.Network{Layers: layers, Weights: append([]float64{}, g.Genes...)}
+}
+
+// MutateWeights adds a gaussian noise to the network weights, each with a given probability.
+func (g *Genome) MutateWeights(rng *rand.Rand, rate float64) {
+	for i := range g.Genes {
+		if rng.Float64() >= rate {
+			continue
+		}
+		g.Genes[i] = clamp(g.Genes[i]+rng.NormFloat64()*weightSigma, -maxWeight, maxWeight)
+	}
+}
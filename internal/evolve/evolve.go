@@ -0,0 +1,206 @@
+// Package evolve searches the boids flocking parameters, or the neural boids networks, with a genetic algorithm.
+// Genomes are evaluated by playing headless games.
+package evolve
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/jtbonhomme/asteboids/internal/config"
+	"github.com/sirupsen/logrus"
+)
+
+// tournamentSize is the number of genomes competing to be selected as a parent.
+const tournamentSize int = 3
+
+// Run evolves conf.Evolve.Generations generations of flocking parameters,
+// and writes out the best genomes as configuration files.
+func Run(log *logrus.Logger, conf *config.Config) error {
+	rng, err := start(log, conf, "Evolve flocking parameters")
+	if err != nil {
+		return err
+	}
+
+	// the current flocking parameters join random ones in the first generation
+	population := []Genome{NewGenome(conf.Flocking)}
+	for len(population) < conf.Evolve.Population {
+		population = append(population, RandomGenome(rng))
+	}
+
+	population, err = search(log, conf, rng, population, func(log *logrus.Logger, g Genome, seed int64) (float64, error) {
+		return Evaluate(log, conf, g, seed)
+	}, (*Genome).Mutate)
+	if err != nil {
+		return err
+	}
+	return writeBest(log, conf, population, "best%d.yml", func(w io.Writer, g Genome) error {
+		return g.WriteConfig(w, conf.Evolve.Fitness, conf.Flocking)
+	})
+}
+
+// start checks the genetic algorithm parameters, and returns its random generator.
+func start(log *logrus.Logger, conf *config.Config, what string) (*rand.Rand, error) {
+	e := conf.Evolve
+	if e.Population < 2 {
+		return nil, errors.New("population must hold at least 2 genomes")
+	}
+	if e.Elite >= e.Population {
+		return nil, errors.New("elite must be smaller than population")
+	}
+	if conf.MaxTPS <= 0 {
+		return nil, errors.New("maxTPS must be strictly positive in evolve mode")
+	}
+	err := checkFitness(e.Fitness)
+	if err != nil {
+		return nil, err
+	}
+	seed := conf.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	log.Infof("%s: %d genomes over %d generations (seed %d, %s fitness)", what, e.Population, e.Generations, seed, e.Fitness)
+	return rand.New(rand.NewSource(seed)), nil
+}
+
+// search evolves the population over conf.Evolve.Generations generations,
+// and returns the last one sorted by decreasing fitness.
+func search(
+	log *logrus.Logger,
+	conf *config.Config,
+	rng *rand.Rand,
+	population []Genome,
+	eval evaluator,
+	mutate mutator) ([]Genome, error) {
+	e := conf.Evolve
+	start := time.Now()
+	for generation := 1; generation <= e.Generations; generation++ {
+		// all genomes of a generation play the same game
+		err := evaluate(population, eval, rng.Int63())
+		if err != nil {
+			return nil, err
+		}
+		sort.SliceStable(population, func(i, j int) bool {
+			return population[i].Fitness > population[j].Fitness
+		})
+		log.Infof("generation %d: best fitness %0.3f, average %0.3f", generation, population[0].Fitness, average(population))
+		if generation < e.Generations {
+			population = breed(rng, population, e.Elite, e.MutationRate, mutate)
+		}
+	}
+	log.Infof("Evolved in %s", time.Since(start).Round(time.Millisecond))
+	return population, nil
+}
+
+// evaluator returns the fitness of a genome, playing a game of a given seed.
+type evaluator func(log *logrus.Logger, g Genome, seed int64) (float64, error)
+
+// mutator mutates the genes of a genome, each with a given probability.
+type mutator func(g *Genome, rng *rand.Rand, rate float64)
+
+// evaluate computes the fitness of all genomes, in parallel.
+func evaluate(population []Genome, eval evaluator, seed int64) error {
+	runLog := logrus.New()
+	runLog.SetOutput(ioutil.Discard)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+	workers := make(chan struct{}, runtime.NumCPU())
+	for i := range population {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(g *Genome) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+			fitness, err := eval(runLog, *g, seed)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+			g.Fitness = fitness
+		}(&population[i])
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// breed returns the next generation: the elite genomes are kept, and the others
+// are children of parents selected by tournament, with mutations.
+// population must be sorted by decreasing fitness.
+func breed(rng *rand.Rand, population []Genome, elite int, mutationRate float64, mutate mutator) []Genome {
+	next := make([]Genome, 0, len(population))
+	for i := 0; i < elite; i++ {
+		next = append(next, Genome{Genes: append([]float64{}, population[i].Genes...)})
+	}
+	for len(next) < len(population) {
+		child := Crossover(rng, tournament(rng, population), tournament(rng, population))
+		mutate(&child, rng, mutationRate)
+		next = append(next, child)
+	}
+	return next
+}
+
+// tournament returns the fittest of a few genomes picked randomly.
+func tournament(rng *rand.Rand, population []Genome) Genome {
+	best := population[rng.Intn(len(population))]
+	for i := 1; i < tournamentSize; i++ {
+		if g := population[rng.Intn(len(population))]; g.Fitness > best.Fitness {
+			best = g
+		}
+	}
+	return best
+}
+
+// average returns the average fitness of the population.
+func average(population []Genome) float64 {
+	sum := 0.0
+	for _, g := range population {
+		sum += g.Fitness
+	}
+	return sum / float64(len(population))
+}
+
+// writeBest writes out the best genomes in the output directory, in files named after a pattern.
+// population must be sorted by decreasing fitness.
+func writeBest(
+	log *logrus.Logger,
+	conf *config.Config,
+	population []Genome,
+	pattern string,
+	write func(w io.Writer, g Genome) error) error {
+	err := os.MkdirAll(conf.Evolve.Output, 0o755)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < conf.Evolve.Best && i < len(population); i++ {
+		name := filepath.Join(conf.Evolve.Output, fmt.Sprintf(pattern, i+1))
+		f, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		err = write(f, population[i])
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+		log.Infof("Saved genome (fitness %0.3f): %s", population[i].Fitness, name)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package evolve_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/config"
+	"github.com/jtbonhomme/asteboids/internal/evolve"
+	"github.com/sirupsen/logrus"
+)
+
+func TestGenome(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	base := config.DefaultFlocking()
+
+	g := evolve.NewGenome(base)
+	if got := g.Flocking(base); got != base {
+		t.Errorf("Flocking() = %+v, want %+v", got, base)
+	}
+
+	a, b := evolve.RandomGenome(rng), evolve.RandomGenome(rng)
+	child := evolve.Crossover(rng, a, b)
+	child.Mutate(rng, 1)
+	f := child.Flocking(base)
+	if f.MaxSpeed < 1 || f.MaxSpeed > 6 || f.FieldOfView < 90 || f.FieldOfView > 360 {
+		t.Errorf("mutated genome out of range: %+v", f)
+	}
+	if f.Walls != base.Walls || f.Occlusion != base.Occlusion {
+		t.Errorf("expected parameters which are not evolved to be kept, got %+v", f)
+	}
+
+	var buf bytes.Buffer
+	if err := child.WriteConfig(&buf, evolve.SurvivalFitness, base); err != nil {
+		t.Fatalf("WriteConfig() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "flocking:\n  maxSpeed: ") {
+		t.Errorf("unexpected config file:\n%s", buf.String())
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+
+	tests := []struct {
+		name    string
+		fitness string
+		wantErr bool
+	}{
+		{name: "survival", fitness: evolve.SurvivalFitness},
+		{name: "compactness", fitness: evolve.CompactnessFitness},
+		{name: "collisions", fitness: evolve.CollisionsFitness},
+		{name: "unknown", fitness: "speed", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conf := &config.Config{
+				Asteroids:    4,
+				Boids:        20,
+				ScreenWidth:  1080,
+				ScreenHeight: 720,
+				MaxTPS:       60,
+				VisionRadius: 75,
+				Collisions:   config.DefaultCollisions(),
+				Restitution:  1,
+				Flocking:     config.DefaultFlocking(),
+				Evolve:       config.DefaultEvolve(),
+			}
+			conf.Evolve.Fitness = tt.fitness
+			conf.Evolve.Ticks = 300
+			fitness, err := evolve.Evaluate(log, conf, evolve.NewGenome(conf.Flocking), 42)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Evaluate() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if fitness < 0 || fitness > 1 {
+				t.Errorf("fitness %f out of [0, 1]", fitness)
+			}
+			// a same game gives a same fitness
+			if again, _ := evolve.Evaluate(log, conf, evolve.NewGenome(conf.Flocking), 42); again != fitness {
+				t.Errorf("got fitness %f then %f for a same game", fitness, again)
+			}
+		})
+	}
+}
+
+func TestEvaluateBrain(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+	rng := rand.New(rand.NewSource(1))
+
+	conf := &config.Config{
+		Asteroids:    4,
+		Boids:        20,
+		ScreenWidth:  1080,
+		ScreenHeight: 720,
+		MaxTPS:       60,
+		VisionRadius: 75,
+		Collisions:   config.DefaultCollisions(),
+		Restitution:  1,
+		Flocking:     config.DefaultFlocking(),
+		Evolve:       config.DefaultEvolve(),
+	}
+	conf.Evolve.Ticks = 300
+
+	layers := evolve.Layers(4)
+	a, b := evolve.RandomBrainGenome(rng, layers), evolve.RandomBrainGenome(rng, layers)
+	child := evolve.Crossover(rng, a, b)
+	child.MutateWeights(rng, 1)
+	brain := child.Network(layers)
+	if len(brain.Weights) != len(a.Genes) {
+		t.Fatalf("got %d weights, want %d", len(brain.Weights), len(a.Genes))
+	}
+
+	fitness, err := evolve.EvaluateBrain(log, conf, brain, 42)
+	if err != nil {
+		t.Fatalf("EvaluateBrain() error = %v", err)
+	}
+	if fitness <= 0 || fitness > 1 {
+		t.Errorf("fitness %f out of ]0, 1]", fitness)
+	}
+	if again, _ := evolve.EvaluateBrain(log, conf, brain, 42); again != fitness {
+		t.Errorf("got fitness %f then %f for a same game", fitness, again)
+	}
+}
@@ -0,0 +1,137 @@
+package evolve
+
+import (
+	"fmt"
+
+	"github.com/jtbonhomme/asteboids/internal/ai"
+	"github.com/jtbonhomme/asteboids/internal/config"
+	"github.com/jtbonhomme/asteboids/internal/game"
+	"github.com/jtbonhomme/asteboids/internal/input"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/sirupsen/logrus"
+)
+
+// Fitness functions scoring genomes, the higher the better.
+const (
+	// SurvivalFitness is the ratio of boids alive at the end of the game.
+	SurvivalFitness string = "survival"
+	// CompactnessFitness is the average of 1/number of flocks, 1 when boids fly as a single flock.
+	CompactnessFitness string = "compactness"
+	// CollisionsFitness decreases with the number of collisions per boid, 1 without any collision.
+	CollisionsFitness string = "collisions"
+)
+
+// metricsEvery is the number of physics steps between two measures of the flocks, when not configured.
+const metricsEvery int = 60
+
+// evolveCollisions are added to the game collision rules to evaluate genomes:
+// boids die on asteroids and bump into each other.
+var evolveCollisions = []string{
+	"boid:asteroid:explode",
+	"boid:rubble:explode",
+	"boid:boid:bounce",
+}
+
+// checkFitness returns an error for an unknown fitness function.
+func checkFitness(fitness string) error {
+	switch fitness {
+	case SurvivalFitness, CompactnessFitness, CollisionsFitness:
+		return nil
+	default:
+		return fmt.Errorf("unknown fitness %s", fitness)
+	}
+}
+
+// Evaluate plays a headless game with the genome flocking parameters, and returns the genome fitness.
+// Boids are not respawned, so that killed boids weigh on the fitness.
+func Evaluate(log *logrus.Logger, conf *config.Config, genome Genome, seed int64) (float64, error) {
+	c := *conf
+	c.Flocking = genome.Flocking(conf.Flocking)
+	c.Neural.Boids = 0
+	return play(log, &c, seed, nil)
+}
+
+// EvaluateBrain plays a headless game with neural boids driven by the brain, and returns the brain fitness.
+// There are as many neural boids as configured, or as classic boids otherwise, which are removed.
+func EvaluateBrain(log *logrus.Logger, conf *config.Config, brain ai.Brain, seed int64) (float64, error) {
+	c := *conf
+	if c.Neural.Boids <= 0 {
+		c.Neural.Boids = c.Boids
+	}
+	c.Neural.Brain = ""
+	c.Boids = 0
+	return play(log, &c, seed, brain)
+}
+
+// play plays a headless game and returns its fitness.
+// The brain drives the neural boids, if any.
+func play(log *logrus.Logger, conf *config.Config, seed int64, brain ai.Brain) (float64, error) {
+	err := checkFitness(conf.Evolve.Fitness)
+	if err != nil {
+		return 0, err
+	}
+	c := *conf
+	c.Species = nil
+	c.Endless = true
+	c.Seed = seed
+	c.Debug = false
+	c.BoidsRespawn = 0
+	c.Collisions = append([]string{}, conf.Collisions...)
+	for _, rule := range evolveCollisions {
+		if !contains(c.Collisions, rule) {
+			c.Collisions = append(c.Collisions, rule)
+		}
+	}
+	if c.Metrics.Every <= 0 {
+		c.Metrics.Every = metricsEvery
+	}
+
+	g := game.New(log, &c, &render.Headless{TPS: float64(c.MaxTPS)}, input.None{})
+	if brain != nil {
+		g.SetBrain(brain)
+	}
+	collisions := 0
+	g.OnCollision(func(e game.CollisionEvent) {
+		if e.A.Type() == physics.BoidAgent || e.B.Type() == physics.BoidAgent {
+			collisions++
+		}
+	})
+	compactness, samples := 0.0, 0
+	g.Events().Subscribe(game.FlocksMeasuredKind, func(e game.Event) {
+		if s := e.(game.FlocksMeasured).Sample; s.Clusters > 0 {
+			compactness += 1 / float64(s.Clusters)
+		}
+		samples++
+	})
+
+	g.StartGame()
+	for i := 0; i < c.Evolve.Ticks; i++ {
+		g.Step()
+	}
+
+	boids := c.Boids + c.Neural.Boids
+	if boids == 0 {
+		return 0, nil
+	}
+	switch c.Evolve.Fitness {
+	case SurvivalFitness:
+		return float64(g.Count(physics.BoidAgent)) / float64(boids), nil
+	case CompactnessFitness:
+		if samples == 0 {
+			return 0, nil
+		}
+		return compactness / float64(samples), nil
+	default:
+		return 1 / (1 + float64(collisions)/float64(boids)), nil
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,123 @@
+package evolve
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+
+	"github.com/jtbonhomme/asteboids/internal/config"
+)
+
+// gene describes an evolved flocking parameter, and its range.
+type gene struct {
+	name  string
+	min   float64
+	max   float64
+	field func(f *config.Flocking) *float64
+}
+
+// genes lists the flocking parameters evolved by the genetic algorithm.
+var genes = []gene{
+	{"maxSpeed", 1, 6, func(f *config.Flocking) *float64 { return &f.MaxSpeed }},
+	{"maxForce", 0.05, 1, func(f *config.Flocking) *float64 { return &f.MaxForce }},
+	{"separation.radius", 10, 200, func(f *config.Flocking) *float64 { return &f.Separation.Radius }},
+	{"separation.weight", 0, 4, func(f *config.Flocking) *float64 { return &f.Separation.Weight }},
+	{"cohesion.radius", 10, 200, func(f *config.Flocking) *float64 { return &f.Cohesion.Radius }},
+	{"cohesion.weight", 0, 4, func(f *config.Flocking) *float64 { return &f.Cohesion.Weight }},
+	{"alignment.radius", 10, 200, func(f *config.Flocking) *float64 { return &f.Alignment.Radius }},
+	{"alignment.weight", 0, 4, func(f *config.Flocking) *float64 { return &f.Alignment.Weight }},
+	{"avoidance.radius", 10, 150, func(f *config.Flocking) *float64 { return &f.Avoidance.Radius }},
+	{"avoidance.weight", 0, 5, func(f *config.Flocking) *float64 { return &f.Avoidance.Weight }},
+	{"flee.radius", 10, 150, func(f *config.Flocking) *float64 { return &f.Flee.Radius }},
+	{"flee.weight", 0, 5, func(f *config.Flocking) *float64 { return &f.Flee.Weight }},
+	{"fieldOfView", 90, 360, func(f *config.Flocking) *float64 { return &f.FieldOfView }},
+}
+
+// Genome is a set of genes, flocking parameters or network weights, and its fitness once evaluated.
+type Genome struct {
+	Genes   []float64
+	Fitness float64
+}
+
+// NewGenome creates a genome from flocking parameters.
+func NewGenome(f config.Flocking) Genome {
+	g := Genome{Genes: make([]float64, len(genes))}
+	for i, spec := range genes {
+		g.Genes[i] = clamp(*spec.field(&f), spec.min, spec.max)
+	}
+	return g
+}
+
+// RandomGenome creates a genome with random flocking parameters.
+func RandomGenome(rng *rand.Rand) Genome {
+	g := Genome{Genes: make([]float64, len(genes))}
+	for i, spec := range genes {
+		g.Genes[i] = spec.min + rng.Float64()*(spec.max-spec.min)
+	}
+	return g
+}
+
+// Flocking returns the flocking parameters of the genome.
+// Parameters which are not evolved are read from base.
+func (g Genome) Flocking(base config.Flocking) config.Flocking {
+	f := base
+	for i, spec := range genes {
+		*spec.field(&f) = g.Genes[i]
+	}
+	return f
+}
+
+// Crossover returns a child genome, whose genes are picked randomly from both parents.
+func Crossover(rng *rand.Rand, a, b Genome) Genome {
+	child := Genome{Genes: make([]float64, len(a.Genes))}
+	for i := range child.Genes {
+		if rng.Intn(2) == 0 {
+			child.Genes[i] = a.Genes[i]
+		} else {
+			child.Genes[i] = b.Genes[i]
+		}
+	}
+	return child
+}
+
+// Mutate adds a gaussian noise to the genes, each with a given probability.
+// The noise standard deviation is a tenth of the gene range.
+func (g *Genome) Mutate(rng *rand.Rand, rate float64) {
+	for i, spec := range genes {
+		if rng.Float64() >= rate {
+			continue
+		}
+		g.Genes[i] = clamp(g.Genes[i]+rng.NormFloat64()*(spec.max-spec.min)/10, spec.min, spec.max)
+	}
+}
+
+// WriteConfig writes out the genome flocking parameters as a configuration file,
+// which can be loaded with the -config-file argument.
+func (g Genome) WriteConfig(w io.Writer, fitness string, base config.Flocking) error {
+	f := g.Flocking(base)
+	rule := func(name string, r config.FlockingRule) string {
+		return fmt.Sprintf("  %s:\n    radius: %g\n    weight: %g\n", name, round(r.Radius), round(r.Weight))
+	}
+	_, err := fmt.Fprintf(w, "# %s fitness: %g\nflocking:\n  maxSpeed: %g\n  maxForce: %g\n%s%s%s%s%s%s%s  fieldOfView: %g\n  occlusion: %t\n",
+		fitness, g.Fitness,
+		round(f.MaxSpeed), round(f.MaxForce),
+		rule("separation", f.Separation),
+		rule("cohesion", f.Cohesion),
+		rule("alignment", f.Alignment),
+		rule("avoidance", f.Avoidance),
+		rule("flee", f.Flee),
+		rule("repulsion", f.Repulsion),
+		rule("walls", f.Walls),
+		round(f.FieldOfView), f.Occlusion)
+	return err
+}
+
+func clamp(v, min, max float64) float64 {
+	return math.Max(min, math.Min(max, v))
+}
+
+// round rounds v to 3 decimals, for a readable configuration file.
+func round(v float64) float64 {
+	return math.Round(v*1000) / 1000
+}
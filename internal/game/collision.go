@@ -0,0 +1,167 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+// Collision handler names, to be used in collision rules.
+const (
+	// ExplodeHandler makes the first agent explode.
+	ExplodeHandler string = "explode"
+	// ExplodeBothHandler makes both agents explode.
+	ExplodeBothHandler string = "explodeBoth"
+	// ShotHandler makes the first agent explode, and destroys the second one (a bullet).
+	// The bullet momentum is transferred to the first agent before it explodes.
+	ShotHandler string = "shot"
+	// BounceHandler makes both agents bounce off each other.
+	BounceHandler string = "bounce"
+	// DeflectHandler makes both agents bounce off each other while the first one is shielded,
+	// else the first agent explodes.
+	DeflectHandler string = "deflect"
+)
+
+// CollisionEvent describes a collision between two agents.
+type CollisionEvent struct {
+	A physics.Physic
+	B physics.Physic
+	// Normal is the unit collision normal, pointing from A to B.
+	Normal vector.Vector2D
+	// Depth is the penetration depth of A and B along Normal.
+	Depth float64
+}
+
+// CollisionHandler proceeds a collision between two agents.
+type CollisionHandler func(CollisionEvent)
+
+// CollisionRule declares that agents of type A collide with agents of type B.
+type CollisionRule struct {
+	A       string
+	B       string
+	Handler string
+}
+
+// String returns the collision rule, as written in the configuration.
+func (r CollisionRule) String() string {
+	return r.A + ":" + r.B + ":" + r.Handler
+}
+
+// ParseCollisionRule reads a collision rule written as "<type A>:<type B>:<handler>",
+// for instance "starship:asteroid:explode".
+func ParseCollisionRule(s string) (CollisionRule, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return CollisionRule{}, fmt.Errorf("invalid collision rule %s, expected <type A>:<type B>:<handler>", s)
+	}
+	return CollisionRule{
+		A:       strings.TrimSpace(parts[0]),
+		B:       strings.TrimSpace(parts[1]),
+		Handler: strings.TrimSpace(parts[2]),
+	}, nil
+}
+
+// collisionHandlers returns the collision handlers, by name.
+func (g *Game) collisionHandlers() map[string]CollisionHandler {
+	return map[string]CollisionHandler{
+		ExplodeHandler: func(e CollisionEvent) {
+			e.A.Explode()
+		},
+		ExplodeBothHandler: func(e CollisionEvent) {
+			e.A.Explode()
+			e.B.Explode()
+		},
+		ShotHandler: func(e CollisionEvent) {
+			momentum := e.B.Velocity()
+			momentum.Multiply(e.B.Mass())
+			e.A.ApplyImpulse(momentum)
+			e.A.Explode()
+			g.Unregister(e.B.ID(), e.B.Type())
+		},
+		BounceHandler: func(e CollisionEvent) {
+			physics.Bounce(e.A, e.B, physics.Contact{Normal: e.Normal, Depth: e.Depth}, g.conf.Restitution)
+		},
+		DeflectHandler: func(e CollisionEvent) {
+			if !shielded(e.A) {
+				e.A.Explode()
+				return
+			}
+			physics.Bounce(e.A, e.B, physics.Contact{Normal: e.Normal, Depth: e.Depth}, g.conf.Restitution)
+		},
+	}
+}
+
+// SetCollisionRules replaces the collision matrix of the game.
+// Rules are checked in order, once per tick.
+func (g *Game) SetCollisionRules(rules []CollisionRule) error {
+	for _, rule := range rules {
+		if _, ok := g.handlers[rule.Handler]; !ok {
+			return fmt.Errorf("unknown collision handler %s in rule %s", rule.Handler, rule)
+		}
+	}
+	g.collisionRules = rules
+	return nil
+}
+
+// OnCollision subscribes a listener to all collision events.
+// Listeners are called after the collision rule handler.
+func (g *Game) OnCollision(listener CollisionHandler) {
+	g.events.Subscribe(CollisionEventKind, func(e Event) {
+		listener(e.(CollisionEvent))
+	})
+}
+
+// DetectCollisions checks all collision rules, and dispatches an event for each collision.
+// An agent destroyed by a collision does not collide anymore during the same tick.
+// When a rule applies to agents of a same type, each pair collides once.
+func (g *Game) DetectCollisions() {
+	for _, rule := range g.collisionRules {
+		rule := rule
+		g.agents.Range(func(a physics.Physic) {
+			for _, b := range g.index.Nearby(a, rule.B) {
+				if !g.agents.Contains(a.ID()) {
+					return
+				}
+				if !g.agents.Contains(b.ID()) {
+					continue
+				}
+				if rule.A == rule.B && b.ID() < a.ID() {
+					continue
+				}
+				if invulnerable(a) || invulnerable(b) {
+					continue
+				}
+				contact, ok := a.Collide(b)
+				if !ok {
+					continue
+				}
+				g.log.Debugf("collision %s: %s [%d , %d] with %s [%d , %d]",
+					rule,
+					a.ID(), int(a.Position().X), int(a.Position().Y),
+					b.ID(), int(b.Position().X), int(b.Position().Y))
+				e := CollisionEvent{
+					A:      a,
+					B:      b,
+					Normal: contact.Normal,
+					Depth:  contact.Depth,
+				}
+				g.handlers[rule.Handler](e)
+				g.events.Publish(e)
+			}
+		}, rule.A)
+	}
+}
+
+// invulnerable returns true for an agent which can not collide for now, like a respawned starship.
+func invulnerable(agent physics.Physic) bool {
+	i, ok := agent.(interface{ Invulnerable() bool })
+	return ok && i.Invulnerable()
+}
+
+// shielded returns true for an agent whose shield is up, like a starship.
+func shielded(agent physics.Physic) bool {
+	s, ok := agent.(interface{ Shielded() bool })
+	return ok && s.Shielded()
+}
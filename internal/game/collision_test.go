@@ -0,0 +1,148 @@
+package game_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/agents"
+	"github.com/jtbonhomme/asteboids/internal/ai"
+	"github.com/jtbonhomme/asteboids/internal/clock"
+	"github.com/jtbonhomme/asteboids/internal/config"
+	"github.com/jtbonhomme/asteboids/internal/game"
+	"github.com/jtbonhomme/asteboids/internal/input"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+)
+
+func TestParseCollisionRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		want    game.CollisionRule
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			rule: "boid:asteroid:explode",
+			want: game.CollisionRule{A: physics.BoidAgent, B: physics.AsteroidAgent, Handler: game.ExplodeHandler},
+		},
+		{
+			name: "spaces",
+			rule: "starship : boid : explodeBoth",
+			want: game.CollisionRule{A: physics.StarshipAgent, B: physics.BoidAgent, Handler: game.ExplodeBothHandler},
+		},
+		{name: "missing handler", rule: "boid:asteroid", wantErr: true},
+		{name: "too many parts", rule: "boid:asteroid:explode:now", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := game.ParseCollisionRule(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseCollisionRule(%s) error = %v, wantErr %t", tt.rule, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseCollisionRule(%s) = %v, want %v", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollisionRules(t *testing.T) {
+	tests := []struct {
+		name       string
+		extraRules []string
+		asteroid   bool
+		boid       bool
+		predator   bool
+		wantOver   bool
+		wantAgents int
+		wantEvents int
+	}{
+		{name: "starship hits asteroid", asteroid: true, wantOver: true, wantAgents: 1, wantEvents: 1},
+		{name: "starship ignores boid", boid: true, wantAgents: 2},
+		{name: "predator hits starship", predator: true, wantOver: true, wantAgents: 1, wantEvents: 1},
+		{
+			name:       "starship hits boid",
+			extraRules: []string{"starship:boid:explodeBoth"},
+			boid:       true,
+			wantOver:   true,
+			wantAgents: 0,
+			wantEvents: 1,
+		},
+		{
+			name:       "boid hits asteroid",
+			extraRules: []string{"boid:asteroid:explode"},
+			asteroid:   true,
+			boid:       true,
+			wantOver:   true,
+			wantAgents: 1,
+			wantEvents: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conf := newTestConfig()
+			conf.Asteroids = 0
+			conf.Boids = 0
+			conf.Collisions = append(config.DefaultCollisions(), tt.extraRules...)
+			g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, input.None{})
+			g.StartGame()
+
+			events := 0
+			g.OnCollision(func(e game.CollisionEvent) {
+				events++
+				if n := e.Normal.MagnitudeSquared(); n < 0.99 || n > 1.01 {
+					t.Errorf("collision normal %v is not a unit vector", e.Normal)
+				}
+			})
+
+			rng := rand.New(rand.NewSource(1))
+			clk := clock.New(60)
+			topo := topology.Toroidal{Width: conf.ScreenWidth, Height: conf.ScreenHeight}
+			x, y := conf.ScreenWidth/2, conf.ScreenHeight/2
+			if tt.asteroid {
+				g.Register(agents.NewAsteroid(newTestLogger(), rng, clk, topo,
+					x+10, y, conf.ScreenWidth, conf.ScreenHeight,
+					g.Register, g.Unregister, nil, nil, false))
+			}
+			if tt.boid {
+				g.Register(ai.NewBoid(newTestLogger(), rng, clk, topo,
+					x-10, y, conf.ScreenWidth, conf.ScreenHeight,
+					g.Unregister, nil, g.Vision, &ai.Flocking{MaxSpeed: 3, MaxForce: 0.3}, false))
+			}
+
+			if tt.predator {
+				g.Register(ai.NewPredator(newTestLogger(), rng, clk, topo,
+					x, y+10, conf.ScreenWidth, conf.ScreenHeight,
+					g.Unregister, nil, g.HuntVision, &ai.Flocking{MaxSpeed: 3, MaxForce: 0.3}, &ai.Hunting{}, false))
+			}
+
+			_ = g.Update()
+
+			if g.IsOver() != tt.wantOver {
+				t.Errorf("game over is %t, want %t", g.IsOver(), tt.wantOver)
+			}
+			if g.AgentsCount() != tt.wantAgents {
+				t.Errorf("got %d agents, want %d", g.AgentsCount(), tt.wantAgents)
+			}
+			if events != tt.wantEvents {
+				t.Errorf("got %d collision events, want %d", events, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestSetCollisionRulesUnknownHandler(t *testing.T) {
+	g := game.New(newTestLogger(), newTestConfig(), &render.Headless{TPS: 60}, input.None{})
+	err := g.SetCollisionRules([]game.CollisionRule{{A: physics.BoidAgent, B: physics.BulletAgent, Handler: "vanish"}})
+	if err == nil {
+		t.Error("expected an error for an unknown collision handler")
+	}
+}
@@ -3,36 +3,28 @@ package game
 import (
 	"fmt"
 	"image/color"
+	"math"
+	"time"
 
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
-	"github.com/hajimehoshi/ebiten/v2/text"
 	"github.com/jtbonhomme/asteboids/internal/fonts"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/render"
 )
 
-// DrawAgents loops over all game agents to update them
-func (g *Game) DrawAgents(screen *ebiten.Image) {
-	for _, s := range g.starships {
-		s.Draw(screen)
-	}
-	for _, a := range g.asteroids {
+// DrawAgents loops over all game agents to draw them
+func (g *Game) DrawAgents(screen render.Screen) {
+	g.agents.Range(func(a physics.Physic) {
 		a.Draw(screen)
-	}
-	for _, b := range g.bullets {
-		b.Draw(screen)
-	}
-	for _, b := range g.boids {
-		b.Draw(screen)
-	}
+	}, physics.StarshipAgent, physics.AsteroidAgent, physics.RubbleAgent, physics.BulletAgent, physics.SaucerBulletAgent, physics.BoidAgent, physics.PredatorAgent, physics.SaucerAgent)
 }
 
 func (g *Game) Score() int {
-	return int(g.gameDuration.Seconds()/g.conf.ScoreTimeUnit) + g.kills*2
+	return int(g.gameDuration.Seconds()/g.conf.ScoreTimeUnit) + g.points
 }
 
 // Draw draws the game screen.
 // Draw is called every frame (typically 1/60[s] for 60Hz display).
-func (g *Game) Draw(screen *ebiten.Image) {
+func (g *Game) Draw(screen render.Screen) {
 	// Erase the image.
 	screen.Fill(g.backgroundColor)
 
@@ -40,24 +32,24 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	g.DrawAgents(screen)
 
 	if g.debug {
-		nAgents := len(g.asteroids) + len(g.starships) + len(g.bullets) + len(g.boids)
-		msg := fmt.Sprintf("TPS: %0.2f\nFPS: %0.2f\nAgents: %d", ebiten.CurrentTPS(), ebiten.CurrentFPS(), nAgents)
-		ebitenutil.DebugPrint(screen, msg)
+		msg := fmt.Sprintf("TPS: %0.2f\nFPS: %0.2f\nAgents: %d\n\n%s\n\n%s", g.renderer.CurrentTPS(), g.renderer.CurrentFPS(), g.AgentsCount(), g.flocks, g.panel)
+		screen.DebugPrint(msg)
 	}
 
 	g.drawTimeElapsed(screen)
 
 	g.drawScore(screen)
+	g.drawLives(screen)
+	g.drawAbilities(screen)
+	if g.intermission && !g.gameOver {
+		g.drawIntermission(screen)
+	}
 	if g.gameOver {
-		if ebiten.IsKeyPressed(ebiten.KeyEnter) {
-			g.RestartGame()
-		}
 		// Title
 		title := "Asteboids"
-		titleTextDim := text.BoundString(fonts.FurturisticRegularFontTitle, title)
+		titleTextDim := screen.BoundString(fonts.FurturisticRegularFontTitle, title)
 		titleTextWidth := titleTextDim.Max.X - titleTextDim.Min.X
-		text.Draw(
-			screen,
+		screen.DrawText(
 			title,
 			fonts.FurturisticRegularFontTitle,
 			int(g.conf.ScreenWidth/2)-titleTextWidth/2,
@@ -79,11 +71,10 @@ func (g *Game) Draw(screen *ebiten.Image) {
 			gameOver = "GAME OVER"
 		}
 
-		gameOverTextDim := text.BoundString(fonts.KarmaticArcadeFont, gameOver)
+		gameOverTextDim := screen.BoundString(fonts.KarmaticArcadeFont, gameOver)
 		gameOverTextWidth := gameOverTextDim.Max.X - gameOverTextDim.Min.X
 		gameOverTextHeight := gameOverTextDim.Max.Y - gameOverTextDim.Min.Y
-		text.Draw(
-			screen,
+		screen.DrawText(
 			gameOver,
 			fonts.KarmaticArcadeFont,
 			int(g.conf.ScreenWidth/2)-gameOverTextWidth/2,
@@ -92,11 +83,10 @@ func (g *Game) Draw(screen *ebiten.Image) {
 		)
 
 		replay := "press   enter   to   play  again"
-		replayTextDim := text.BoundString(fonts.ArcadeClassicFont, replay)
+		replayTextDim := screen.BoundString(fonts.ArcadeClassicFont, replay)
 		replayTextWidth := replayTextDim.Max.X - replayTextDim.Min.X
 		replayTextHeight := replayTextDim.Max.Y - replayTextDim.Min.Y
-		text.Draw(
-			screen,
+		screen.DrawText(
 			replay,
 			fonts.ArcadeClassicFont,
 			int(g.conf.ScreenWidth/2)-replayTextWidth/2,
@@ -106,13 +96,42 @@ func (g *Game) Draw(screen *ebiten.Image) {
 	}
 }
 
-func (g *Game) drawScore(screen *ebiten.Image) {
+func (g *Game) drawIntermission(screen render.Screen) {
+	// Banner of the next level
+	next := g.levels()[g.level+1]
+	banner := fmt.Sprintf("LEVEL  %d", g.level+2)
+	bannerTextDim := screen.BoundString(fonts.KarmaticArcadeFont, banner)
+	bannerTextWidth := bannerTextDim.Max.X - bannerTextDim.Min.X
+	bannerTextHeight := bannerTextDim.Max.Y - bannerTextDim.Min.Y
+	screen.DrawText(
+		banner,
+		fonts.KarmaticArcadeFont,
+		int(g.conf.ScreenWidth/2)-bannerTextWidth/2,
+		int(g.conf.ScreenHeight/2)-bannerTextHeight/2,
+		color.Gray16{0xffff},
+	)
+
+	if next.Name == "" {
+		return
+	}
+	nameTextDim := screen.BoundString(fonts.ArcadeClassicFont, next.Name)
+	nameTextWidth := nameTextDim.Max.X - nameTextDim.Min.X
+	nameTextHeight := nameTextDim.Max.Y - nameTextDim.Min.Y
+	screen.DrawText(
+		next.Name,
+		fonts.ArcadeClassicFont,
+		int(g.conf.ScreenWidth/2)-nameTextWidth/2,
+		int(g.conf.ScreenHeight/2)+bannerTextHeight/2+nameTextHeight/2,
+		color.Gray16{0xbbbf},
+	)
+}
+
+func (g *Game) drawScore(screen render.Screen) {
 	// Score
 	score := fmt.Sprintf("Score %d", g.Score())
-	scoreTextDim := text.BoundString(fonts.FurturisticRegularFontMenu, score)
+	scoreTextDim := screen.BoundString(fonts.FurturisticRegularFontMenu, score)
 	scoreTextHeight := scoreTextDim.Max.Y - scoreTextDim.Min.Y
-	text.Draw(
-		screen,
+	screen.DrawText(
 		score,
 		fonts.FurturisticRegularFontMenu,
 		900,
@@ -121,13 +140,71 @@ func (g *Game) drawScore(screen *ebiten.Image) {
 	)
 }
 
-func (g *Game) drawTimeElapsed(screen *ebiten.Image) {
+func (g *Game) drawLives(screen render.Screen) {
+	// Lives left, as small starships pointing up under the score
+	const iconScale, iconSpacing = 0.4, 25
+	if g.starshipImage == nil {
+		return
+	}
+	w, h := g.starshipImage.Size()
+	for i := 0; i < g.lives; i++ {
+		geoM := render.GeoM{}
+		geoM.Translate(-float64(w)/2, -float64(h)/2)
+		geoM.Rotate(-math.Pi / 2)
+		geoM.Scale(iconScale, iconScale)
+		geoM.Translate(float64(910+i*iconSpacing), 50)
+		screen.DrawImage(g.starshipImage, geoM)
+	}
+}
+
+func (g *Game) drawAbilities(screen render.Screen) {
+	// Shield energy bar and hyperspace cooldown, under the lives
+	const x, y, width, height = 900, 70, 100, 6
+	type abilities interface {
+		Energy() float64
+		HyperspaceCooldown() time.Duration
+	}
+	var ship abilities
+	for _, a := range g.agents.OfType(physics.StarshipAgent) {
+		if s, ok := a.(abilities); ok {
+			ship = s
+			break
+		}
+	}
+	if ship == nil {
+		return
+	}
+
+	frame := color.Gray16{0x8888}
+	screen.DrawLine(x, y, x+width, y, frame)
+	screen.DrawLine(x+width, y, x+width, y+height, frame)
+	screen.DrawLine(x+width, y+height, x, y+height, frame)
+	screen.DrawLine(x, y+height, x, y, frame)
+	for i := 1; i < height; i++ {
+		screen.DrawLine(x+1, float64(y+i), x+1+(width-2)*ship.Energy(), float64(y+i), color.Gray16{0xffff})
+	}
+
+	hyperspace := "Hyperspace ready"
+	if cooldown := ship.HyperspaceCooldown(); cooldown > 0 {
+		hyperspace = fmt.Sprintf("Hyperspace %0.1fs", cooldown.Seconds())
+	}
+	hyperspaceTextDim := screen.BoundString(fonts.FurturisticRegularFontMenu, hyperspace)
+	hyperspaceTextHeight := hyperspaceTextDim.Max.Y - hyperspaceTextDim.Min.Y
+	screen.DrawText(
+		hyperspace,
+		fonts.FurturisticRegularFontMenu,
+		x,
+		y+height+hyperspaceTextHeight+10,
+		color.Gray16{0xbbbf},
+	)
+}
+
+func (g *Game) drawTimeElapsed(screen render.Screen) {
 	// Time elapsed
 	elapsed := "Time elapsed " + g.gameDuration.String()
-	elapsedTextDim := text.BoundString(fonts.FurturisticRegularFontMenu, elapsed)
+	elapsedTextDim := screen.BoundString(fonts.FurturisticRegularFontMenu, elapsed)
 	elapsedTextHeight := elapsedTextDim.Max.Y - elapsedTextDim.Min.Y
-	text.Draw(
-		screen,
+	screen.DrawText(
 		elapsed,
 		fonts.FurturisticRegularFontMenu,
 		100,
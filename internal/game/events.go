@@ -0,0 +1,209 @@
+package game
+
+import (
+	"time"
+
+	"github.com/jtbonhomme/asteboids/internal/metrics"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+)
+
+// EventKind identifies a kind of game event.
+type EventKind string
+
+// Game event kinds.
+const (
+	CollisionEventKind    EventKind = "collision"
+	AsteroidDestroyedKind EventKind = "asteroidDestroyed"
+	RubbleDestroyedKind   EventKind = "rubbleDestroyed"
+	ShipDestroyedKind     EventKind = "shipDestroyed"
+	BulletFiredKind       EventKind = "bulletFired"
+	ShipThrustedKind      EventKind = "shipThrusted"
+	BoidSpawnedKind       EventKind = "boidSpawned"
+	BoidDestroyedKind     EventKind = "boidDestroyed"
+	GameOverKind          EventKind = "gameOver"
+	FlocksMeasuredKind    EventKind = "flocksMeasured"
+	ExtraLifeWonKind      EventKind = "extraLifeWon"
+	SaucerSpawnedKind     EventKind = "saucerSpawned"
+	SaucerDestroyedKind   EventKind = "saucerDestroyed"
+	LevelStartedKind      EventKind = "levelStarted"
+	LevelClearedKind      EventKind = "levelCleared"
+)
+
+// Event is published on the game event bus.
+type Event interface {
+	// Kind returns the event kind, which handlers subscribe to.
+	Kind() EventKind
+}
+
+// EventHandler handles events of the kind it subscribed to.
+type EventHandler func(Event)
+
+// EventBus dispatches game events synchronously, to handlers in subscription order.
+type EventBus struct {
+	handlers map[EventKind][]EventHandler
+}
+
+// NewEventBus creates an event bus without subscribers.
+func NewEventBus() *EventBus {
+	return &EventBus{
+		handlers: make(map[EventKind][]EventHandler),
+	}
+}
+
+// Subscribe registers a handler for a kind of events.
+func (b *EventBus) Subscribe(kind EventKind, handler EventHandler) {
+	b.handlers[kind] = append(b.handlers[kind], handler)
+}
+
+// Publish calls all handlers subscribed to the event kind, before returning.
+func (b *EventBus) Publish(e Event) {
+	for _, handler := range b.handlers[e.Kind()] {
+		handler(e)
+	}
+}
+
+// Kind returns the event kind.
+func (e CollisionEvent) Kind() EventKind { return CollisionEventKind }
+
+// AsteroidDestroyed is published when an asteroid leaves the game.
+type AsteroidDestroyed struct {
+	Asteroid physics.Physic
+}
+
+// Kind returns the event kind.
+func (e AsteroidDestroyed) Kind() EventKind { return AsteroidDestroyedKind }
+
+// RubbleDestroyed is published when a rubble leaves the game.
+type RubbleDestroyed struct {
+	Rubble physics.Physic
+}
+
+// Kind returns the event kind.
+func (e RubbleDestroyed) Kind() EventKind { return RubbleDestroyedKind }
+
+// ShipDestroyed is published when a starship leaves the game.
+type ShipDestroyed struct {
+	Ship physics.Physic
+}
+
+// Kind returns the event kind.
+func (e ShipDestroyed) Kind() EventKind { return ShipDestroyedKind }
+
+// BulletFired is published when a bullet enters the game.
+type BulletFired struct {
+	Bullet physics.Physic
+}
+
+// Kind returns the event kind.
+func (e BulletFired) Kind() EventKind { return BulletFiredKind }
+
+// ShipThrusted is published every physics step a starship accelerates.
+type ShipThrusted struct {
+	Ship physics.Physic
+}
+
+// Kind returns the event kind.
+func (e ShipThrusted) Kind() EventKind { return ShipThrustedKind }
+
+// BoidSpawned is published when a boid enters the game.
+type BoidSpawned struct {
+	Boid physics.Physic
+}
+
+// Kind returns the event kind.
+func (e BoidSpawned) Kind() EventKind { return BoidSpawnedKind }
+
+// BoidDestroyed is published when a boid leaves the game.
+type BoidDestroyed struct {
+	Boid physics.Physic
+}
+
+// Kind returns the event kind.
+func (e BoidDestroyed) Kind() EventKind { return BoidDestroyedKind }
+
+// GameOver is published when the game ends.
+type GameOver struct {
+	Won      bool
+	Score    int
+	Duration time.Duration
+}
+
+// Kind returns the event kind.
+func (e GameOver) Kind() EventKind { return GameOverKind }
+
+// FlocksMeasured is published when the flocking metrics are measured.
+type FlocksMeasured struct {
+	Sample metrics.Sample
+}
+
+// Kind returns the event kind.
+func (e FlocksMeasured) Kind() EventKind { return FlocksMeasuredKind }
+
+// ExtraLifeWon is published when the player wins an extra life.
+type ExtraLifeWon struct {
+	Lives int
+}
+
+// Kind returns the event kind.
+func (e ExtraLifeWon) Kind() EventKind { return ExtraLifeWonKind }
+
+// SaucerSpawned is published when a flying saucer enters the game.
+type SaucerSpawned struct {
+	Saucer physics.Physic
+}
+
+// Kind returns the event kind.
+func (e SaucerSpawned) Kind() EventKind { return SaucerSpawnedKind }
+
+// SaucerDestroyed is published when a flying saucer leaves the game, shot or past the screen.
+type SaucerDestroyed struct {
+	Saucer physics.Physic
+}
+
+// Kind returns the event kind.
+func (e SaucerDestroyed) Kind() EventKind { return SaucerDestroyedKind }
+
+// LevelStarted is published when a level starts, numbered from 1.
+type LevelStarted struct {
+	Level int
+	Name  string
+}
+
+// Kind returns the event kind.
+func (e LevelStarted) Kind() EventKind { return LevelStartedKind }
+
+// LevelCleared is published when the win condition of a level is met.
+type LevelCleared struct {
+	Level int
+}
+
+// Kind returns the event kind.
+func (e LevelCleared) Kind() EventKind { return LevelClearedKind }
+
+// publishSpawn publishes the event matching an agent entering the game.
+func (g *Game) publishSpawn(agent physics.Physic) {
+	switch agent.Type() {
+	case physics.BulletAgent:
+		g.events.Publish(BulletFired{Bullet: agent})
+	case physics.BoidAgent:
+		g.events.Publish(BoidSpawned{Boid: agent})
+	case physics.SaucerAgent:
+		g.events.Publish(SaucerSpawned{Saucer: agent})
+	}
+}
+
+// publishDestroy publishes the event matching an agent leaving the game.
+func (g *Game) publishDestroy(agent physics.Physic) {
+	switch agent.Type() {
+	case physics.AsteroidAgent:
+		g.events.Publish(AsteroidDestroyed{Asteroid: agent})
+	case physics.RubbleAgent:
+		g.events.Publish(RubbleDestroyed{Rubble: agent})
+	case physics.StarshipAgent:
+		g.events.Publish(ShipDestroyed{Ship: agent})
+	case physics.BoidAgent:
+		g.events.Publish(BoidDestroyed{Boid: agent})
+	case physics.SaucerAgent:
+		g.events.Publish(SaucerDestroyed{Saucer: agent})
+	}
+}
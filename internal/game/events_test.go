@@ -0,0 +1,240 @@
+package game_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/agents"
+	"github.com/jtbonhomme/asteboids/internal/clock"
+	"github.com/jtbonhomme/asteboids/internal/config"
+	"github.com/jtbonhomme/asteboids/internal/game"
+	"github.com/jtbonhomme/asteboids/internal/input"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+func TestEventBus(t *testing.T) {
+	bus := game.NewEventBus()
+	calls := []string{}
+	bus.Subscribe(game.GameOverKind, func(e game.Event) {
+		calls = append(calls, "first")
+	})
+	bus.Subscribe(game.GameOverKind, func(e game.Event) {
+		if e.(game.GameOver).Score != 12 {
+			t.Errorf("got score %d, want 12", e.(game.GameOver).Score)
+		}
+		calls = append(calls, "second")
+	})
+	bus.Subscribe(game.BulletFiredKind, func(e game.Event) {
+		calls = append(calls, "bullet")
+	})
+
+	bus.Publish(game.GameOver{Score: 12})
+	if len(calls) != 2 || calls[0] != "first" || calls[1] != "second" {
+		t.Errorf("got handler calls %v, want [first second]", calls)
+	}
+}
+
+func TestGameEvents(t *testing.T) {
+	conf := newTestConfig()
+	g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, shooter{})
+	counts := make(map[game.EventKind]int)
+	for _, kind := range []game.EventKind{
+		game.AsteroidDestroyedKind,
+		game.RubbleDestroyedKind,
+		game.BulletFiredKind,
+		game.BoidSpawnedKind,
+	} {
+		g.Events().Subscribe(kind, func(e game.Event) {
+			counts[e.Kind()]++
+		})
+	}
+	g.StartGame()
+	for i := 0; i < 1200; i++ {
+		_ = g.Update()
+	}
+
+	if counts[game.BoidSpawnedKind] != conf.Boids {
+		t.Errorf("got %d boids spawned, want %d", counts[game.BoidSpawnedKind], conf.Boids)
+	}
+	if counts[game.BulletFiredKind] == 0 {
+		t.Error("expected bullets to be fired")
+	}
+	kills := counts[game.AsteroidDestroyedKind] + counts[game.RubbleDestroyedKind]
+	if kills == 0 {
+		t.Error("expected asteroids to be destroyed")
+	}
+	if g.Score() < 2*kills {
+		t.Errorf("got score %d, want at least %d for %d kills", g.Score(), 2*kills, kills)
+	}
+}
+
+func TestShipThrusted(t *testing.T) {
+	tests := []struct {
+		name string
+		in   input.Input
+		want int
+	}{
+		{name: "thrust", in: keys{input.KeyUp: true}, want: 10},
+		{name: "no thrust", in: keys{}, want: 0},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conf := newTestConfig()
+			conf.Asteroids = 0
+			conf.Boids = 0
+			g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, tt.in)
+			g.AddStarship()
+			got := 0
+			g.Events().Subscribe(game.ShipThrustedKind, func(e game.Event) {
+				got++
+			})
+			for i := 0; i < 10; i++ {
+				g.Step()
+			}
+			if got != tt.want {
+				t.Errorf("got %d thrust events, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShootBoids(t *testing.T) {
+	conf := newTestConfig()
+	conf.BoidScore = 5
+	conf.BoidsRespawn = 1
+	conf.Panic = config.DefaultPanic()
+	g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, shooter{})
+	counts := make(map[game.EventKind]int)
+	for _, kind := range []game.EventKind{
+		game.AsteroidDestroyedKind,
+		game.RubbleDestroyedKind,
+		game.BoidSpawnedKind,
+		game.BoidDestroyedKind,
+	} {
+		g.Events().Subscribe(kind, func(e game.Event) {
+			counts[e.Kind()]++
+		})
+	}
+	g.StartGame()
+	for i := 0; i < 3600; i++ {
+		_ = g.Update()
+	}
+
+	shot := counts[game.BoidDestroyedKind]
+	if shot == 0 {
+		t.Fatal("expected boids to be shot")
+	}
+	if counts[game.BoidSpawnedKind] <= conf.Boids {
+		t.Errorf("got %d boids spawned, want more than %d", counts[game.BoidSpawnedKind], conf.Boids)
+	}
+	kills := counts[game.AsteroidDestroyedKind] + counts[game.RubbleDestroyedKind]
+	if g.Score() < 2*kills+conf.BoidScore*shot {
+		t.Errorf("got score %d, want at least %d for %d kills and %d boids shot", g.Score(), 2*kills+conf.BoidScore*shot, kills, shot)
+	}
+}
+
+func TestRespawnBoids(t *testing.T) {
+	conf := newTestConfig()
+	conf.Asteroids = 0
+	conf.BoidsRespawn = 1
+	g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, input.None{})
+	var boids []physics.Physic
+	g.Events().Subscribe(game.BoidSpawnedKind, func(e game.Event) {
+		boids = append(boids, e.(game.BoidSpawned).Boid)
+	})
+	g.StartGame()
+
+	for _, b := range boids[:conf.Boids/2] {
+		b.Explode()
+	}
+	for i := 0; i < 60; i++ {
+		g.Step()
+	}
+	if got := g.Count(physics.BoidAgent); got != conf.Boids {
+		t.Errorf("got %d boids after a respawn, want %d", got, conf.Boids)
+	}
+}
+
+func TestSaucers(t *testing.T) {
+	tests := []struct {
+		name      string
+		small     bool
+		wantScore int
+	}{
+		{name: "big saucer shot", wantScore: 20},
+		{name: "small saucer shot", small: true, wantScore: 100},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conf := newTestConfig()
+			conf.Asteroids = 0
+			conf.Boids = 0
+			conf.Saucer = config.DefaultSaucer()
+			conf.Saucer.Every = 0
+			g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, input.None{})
+			destroyed := 0
+			g.Events().Subscribe(game.SaucerDestroyedKind, func(e game.Event) {
+				destroyed++
+			})
+			g.StartGame()
+
+			rng := rand.New(rand.NewSource(1))
+			clk := clock.New(60)
+			topo := topology.Toroidal{Width: conf.ScreenWidth, Height: conf.ScreenHeight}
+			s := agents.NewSaucer(newTestLogger(), rng, clk, topo, tt.small, conf.ScreenWidth, conf.ScreenHeight,
+				g.Register, g.Unregister, g.SaucerVision, nil, nil, false)
+			position := vector.Vector2D{X: 100, Y: 100}
+			s.Move(position)
+			g.Register(s)
+			g.Register(agents.NewBullet(newTestLogger(), rng, clk, topo, position.X, position.Y, 0,
+				conf.ScreenWidth, conf.ScreenHeight, g.Unregister, nil))
+			g.Step()
+
+			if destroyed != 1 {
+				t.Errorf("got %d saucers destroyed, want 1", destroyed)
+			}
+			if g.Score() != tt.wantScore {
+				t.Errorf("got score %d, want %d", g.Score(), tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestSaucersCrossing(t *testing.T) {
+	conf := newTestConfig()
+	conf.Asteroids = 0
+	conf.Boids = 0
+	conf.Saucer = config.DefaultSaucer()
+	conf.Saucer.Every = 2
+	conf.Saucer.Small = 0.5
+	g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, input.None{})
+	counts := make(map[game.EventKind]int)
+	for _, kind := range []game.EventKind{
+		game.SaucerSpawnedKind,
+		game.SaucerDestroyedKind,
+	} {
+		g.Events().Subscribe(kind, func(e game.Event) {
+			counts[e.Kind()]++
+		})
+	}
+	g.StartGame()
+	for i := 0; i < 60*60; i++ {
+		g.Step()
+	}
+
+	if counts[game.SaucerSpawnedKind] == 0 || counts[game.SaucerDestroyedKind] == 0 {
+		t.Errorf("expected saucers to cross the screen, got %v", counts)
+	}
+	if !g.IsOver() {
+		t.Error("expected saucers to shoot the starship")
+	}
+}
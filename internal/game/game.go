@@ -6,101 +6,261 @@ import (
 	"math/rand"
 	"time"
 
-	"github.com/hajimehoshi/ebiten/v2"
 	"github.com/jtbonhomme/asteboids/internal/agents"
 	"github.com/jtbonhomme/asteboids/internal/ai"
+	"github.com/jtbonhomme/asteboids/internal/clock"
 	"github.com/jtbonhomme/asteboids/internal/config"
 	"github.com/jtbonhomme/asteboids/internal/images"
+	"github.com/jtbonhomme/asteboids/internal/input"
+	"github.com/jtbonhomme/asteboids/internal/metrics"
 	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/registry"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/jtbonhomme/asteboids/internal/sounds"
+	"github.com/jtbonhomme/asteboids/internal/spatial"
+	"github.com/jtbonhomme/asteboids/internal/topology"
 	"github.com/sirupsen/logrus"
 )
 
 type Game struct {
-	log             *logrus.Logger
-	conf            *config.Config
-	gameOver        bool
-	gameWon         bool
-	mute            bool
-	startTime       time.Time
-	gameDuration    time.Duration
-	highestDuration time.Duration
-	highScore       int
-	kills           int
-	debug           bool
-	backgroundColor color.RGBA
-	starships       map[string]physics.Physic
-	asteroids       map[string]physics.Physic
-	bullets         map[string]physics.Physic
-	boids           map[string]physics.Physic
-	starshipImage   *ebiten.Image
-	bulletImage     *ebiten.Image
-	boidImage       *ebiten.Image
-	asteroidImages  []*ebiten.Image
-	rubbleImages    []*ebiten.Image
+	log              *logrus.Logger
+	conf             *config.Config
+	renderer         render.Renderer
+	input            input.Input
+	rand             *rand.Rand
+	clock            *clock.Clock
+	integrator       physics.Integrator
+	accumulator      int
+	elapsed          time.Duration
+	lastUpdate       time.Time
+	topology         topology.Topology
+	gameOver         bool
+	gameWon          bool
+	mute             bool
+	gameDuration     time.Duration
+	highestDuration  time.Duration
+	highScore        int
+	points           int
+	lives            int
+	nextExtraLife    int
+	nextBoidsRespawn time.Duration
+	nextSaucer       time.Duration
+	level            int
+	levelStart       time.Duration
+	levelPoints      int
+	intermission     bool
+	nextLevel        time.Duration
+	flocks           metrics.Sample
+	debug            bool
+	backgroundColor  color.RGBA
+	agents           *registry.Registry
+	index            *spatial.Grid
+	handlers         map[string]CollisionHandler
+	collisionRules   []CollisionRule
+	events           *EventBus
+	species          []*boidSpecies
+	predatorFlocking *ai.Flocking
+	hunting          *ai.Hunting
+	abilities        *agents.Abilities
+	panel            *debugPanel
+	starshipImage    render.Image
+	bulletImage      render.Image
+	predatorImage    render.Image
+	bigSaucerImage   render.Image
+	smallSaucerImage render.Image
+	asteroidImages   []render.Image
+	splitting        *agents.Splitting
 }
 
+// New creates a game, which draws with renderer and reads player's commands from in.
 func New(log *logrus.Logger,
-	conf *config.Config) *Game {
-	log.Infof("New Game")
-	rand.Seed(time.Now().UnixNano())
+	conf *config.Config,
+	renderer render.Renderer,
+	in input.Input) *Game {
+	seed := conf.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	log.Infof("New Game (seed %d)", seed)
+	topo, err := topology.New(conf.Topology, conf.ScreenWidth, conf.ScreenHeight)
+	if err != nil {
+		log.Errorf("error when creating world topology: %s", err.Error())
+		topo = topology.Toroidal{Width: conf.ScreenWidth, Height: conf.ScreenHeight}
+	}
+	integrator, err := physics.NewIntegrator(conf.Integrator)
+	if err != nil {
+		log.Errorf("error when creating physics integrator: %s", err.Error())
+		integrator = physics.SemiImplicitEuler{}
+	}
 	g := &Game{
 		log:             log,
 		conf:            conf,
+		renderer:        renderer,
+		input:           in,
+		rand:            rand.New(rand.NewSource(seed)),
+		clock:           clock.New(conf.PhysicsTPS),
+		integrator:      integrator,
+		topology:        topo,
 		gameOver:        false,
 		gameWon:         false,
 		mute:            conf.Mute,
-		startTime:       time.Now(),
 		gameDuration:    0,
-		kills:           0,
+		points:          0,
 		highScore:       0,
 		highestDuration: 0,
 		debug:           conf.Debug,
 		backgroundColor: color.RGBA{0x10, 0x10, 0x10, 0xff},
-		starships:       make(map[string]physics.Physic),
-		asteroids:       make(map[string]physics.Physic),
-		bullets:         make(map[string]physics.Physic),
-		boids:           make(map[string]physics.Physic),
-		asteroidImages:  make([]*ebiten.Image, 5),
-		rubbleImages:    make([]*ebiten.Image, 5),
+		agents:          registry.New(),
+		events:          NewEventBus(),
+		hunting: &ai.Hunting{
+			Weight:        conf.Predator.Weight,
+			ArriveRadius:  conf.Predator.ArriveRadius,
+			MaxPrediction: conf.Predator.MaxPrediction,
+		},
+		abilities: &agents.Abilities{
+			HyperspaceCooldown: time.Duration(conf.Hyperspace.Cooldown * float64(time.Second)),
+			HyperspaceFailure:  conf.Hyperspace.Failure,
+			ShieldDrain:        conf.Shield.Drain,
+			ShieldRecharge:     conf.Shield.Recharge,
+		},
+		index:          spatial.NewGrid(topo, conf.ScreenWidth, conf.ScreenHeight, conf.VisionRadius),
+		asteroidImages: make([]render.Image, 5),
+		splitting: &agents.Splitting{
+			Count:  conf.Asteroid.Split,
+			Spread: conf.Asteroid.Spread,
+		},
 	}
 
+	medium, small := make([]render.Image, 5), make([]render.Image, 5)
 	for i := 0; i < 5; i++ {
-		aFilename := fmt.Sprintf("asteroid%d.png", i)
-		asteroidImage, err := images.LoadImageFromSlice(int(g.conf.ScreenWidth), int(g.conf.ScreenHeight), aFilename)
-		if err != nil {
-			log.Errorf("error when loading image from file: %s", err.Error())
+		g.asteroidImages[i] = g.loadImage(fmt.Sprintf("asteroid%d.png", i))
+		medium[i] = g.loadImage(fmt.Sprintf("medium%d.png", i))
+		small[i] = g.loadImage(fmt.Sprintf("rubble%d.png", i))
+	}
+	g.splitting.Images[agents.LargeTier] = g.asteroidImages
+	g.splitting.Images[agents.MediumTier] = medium
+	g.splitting.Images[agents.SmallTier] = small
+	g.starshipImage = g.loadImage("ship.png")
+	g.bulletImage = g.loadImage("bullet.png")
+	speciesConf := conf.Species
+	if len(speciesConf) == 0 {
+		speciesConf = []config.Species{config.DefaultSpecies(conf.Boids)}
+	}
+	for _, s := range speciesConf {
+		g.species = append(g.species, g.newSpecies(s))
+	}
+	if conf.Neural.Boids > 0 {
+		if s := g.newNeuralSpecies(); s != nil {
+			g.species = append(g.species, s)
 		}
-		g.asteroidImages[i] = asteroidImage
-		rFilename := fmt.Sprintf("rubble%d.png", i)
-		rubbleImage, err := images.LoadImageFromSlice(int(g.conf.ScreenWidth), int(g.conf.ScreenHeight), rFilename)
-		if err != nil {
-			log.Errorf("error when loading image from file: %s", err.Error())
+	}
+	for i, l := range conf.Levels {
+		switch l.Win {
+		case "", config.ClearWin, config.ScoreWin, config.SurviveWin:
+		default:
+			log.Errorf("unknown win condition %s of level %d, which is won once cleared", l.Win, i+1)
 		}
-		g.rubbleImages[i] = rubbleImage
 	}
-	starshipImage, err := images.LoadImageFromSlice(int(g.conf.ScreenWidth), int(g.conf.ScreenHeight), "ship.png")
-	if err != nil {
-		log.Errorf("error when loading image from file: %s", err.Error())
+	g.predatorImage = g.renderer.NewImage(images.Boid(14, 14, color.RGBA{200, 60, 60, 255}))
+	g.bigSaucerImage = g.renderer.NewImage(images.Saucer(40, 24, color.RGBA{200, 200, 200, 255}))
+	g.smallSaucerImage = g.renderer.NewImage(images.Saucer(20, 12, color.RGBA{200, 200, 200, 255}))
+
+	// predators flock like boids, at their own speed
+	g.predatorFlocking = newFlocking(conf.Flocking)
+	g.predatorFlocking.MaxSpeed = conf.Predator.MaxSpeed
+	g.predatorFlocking.MaxForce = conf.Predator.MaxForce
+
+	g.panel = g.newFlockingPanel()
+	g.agents.OnDestroy(func(agent physics.Physic) {
+		g.index.RemoveID(agent.ID())
+	})
+	g.agents.OnSpawn(g.publishSpawn)
+	g.agents.OnDestroy(g.publishDestroy)
+	g.subscribeScoring()
+	g.subscribeRespawn()
+	g.subscribePanic()
+	g.subscribeAudio()
+	g.subscribeLogging()
+
+	g.handlers = g.collisionHandlers()
+	rules := []CollisionRule{}
+	for _, s := range conf.Collisions {
+		rule, err := ParseCollisionRule(s)
+		if err != nil {
+			log.Errorf("error when reading collision rule: %s", err.Error())
+			continue
+		}
+		rules = append(rules, rule)
 	}
-	g.starshipImage = starshipImage
-	bulletImage, err := images.LoadImageFromSlice(int(g.conf.ScreenWidth), int(g.conf.ScreenHeight), "bullet.png")
+	err = g.SetCollisionRules(rules)
 	if err != nil {
-		log.Errorf("error when loading image from file: %s", err.Error())
+		log.Errorf("error when setting collision rules: %s", err.Error())
 	}
-	g.bulletImage = bulletImage
-	boidImage, err := images.LoadImageFromSlice(int(g.conf.ScreenWidth), int(g.conf.ScreenHeight), "boid.png")
+	return g
+}
+
+// loadImage creates a renderer image from an embedded image file.
+func (g *Game) loadImage(name string) render.Image {
+	rawImage, err := images.LoadImageFromSlice(name)
 	if err != nil {
-		log.Errorf("error when loading image from file: %s", err.Error())
+		g.log.Errorf("error when loading image from file: %s", err.Error())
+		return nil
 	}
-	g.boidImage = boidImage
-	return g
+	return g.renderer.NewImage(rawImage)
 }
 
 // StartGame initializes a new game.
+// Asteroids and boids are added by the first level, if any.
 func (g *Game) StartGame() {
-	// add starship
+	g.AddStarship()
+
+	if g.levels() == nil {
+		// add asteroids
+		for i := 0; i < g.conf.Asteroids; i++ {
+			g.AddAsteroid(g.asteroidImages[g.rand.Intn(5)])
+		}
+
+		// add boids of each species
+		for _, s := range g.species {
+			s.population = s.boids
+			for i := 0; i < s.boids; i++ {
+				g.AddBoid(s.name)
+			}
+		}
+	}
+
+	// add predators
+	for i := 0; i < g.conf.Predators; i++ {
+		g.AddPredator()
+	}
+
+	g.clock.Reset()
+	g.gameDuration = 0
+	g.gameOver = false
+	g.gameWon = false
+	g.points = 0
+	g.lives = g.conf.Lives - 1
+	if g.lives < 0 {
+		g.lives = 0
+	}
+	g.nextExtraLife = g.conf.ExtraLife
+	g.nextBoidsRespawn = 0
+	g.level = 0
+	g.intermission = false
+	if g.levels() != nil {
+		g.startLevel()
+	} else {
+		g.nextSaucer = g.saucerDelay()
+	}
+}
+
+// AddStarship insert a new starship at the center of the screen.
+func (g *Game) AddStarship() *agents.Starship {
 	p := agents.NewStarship(
 		g.log,
+		g.rand,
+		g.clock,
+		g.topology,
 		g.conf.ScreenWidth/2,
 		g.conf.ScreenHeight/2,
 		g.conf.ScreenWidth,
@@ -108,130 +268,277 @@ func (g *Game) StartGame() {
 		g.Register,
 		g.Unregister,
 		g.Vision,
+		g.input,
 		g.starshipImage,
 		g.bulletImage,
+		g.abilities,
 		g.debug)
 	g.Register(p)
-
-	// add asteroids
-	for i := 0; i < g.conf.Asteroids; i++ {
-		g.AddAsteroid(g.asteroidImages[rand.Intn(5)])
-	}
-
-	// add boids
-	for i := 0; i < g.conf.Boids; i++ {
-		g.AddBoid()
-	}
-
-	g.startTime = time.Now()
-	g.gameDuration = 0
-	g.gameOver = false
-	g.gameWon = false
-	g.kills = 0
+	return p
 }
 
 // AddAsteroid insert a new asteroid in the game.
-func (g *Game) AddAsteroid(asteroidImage *ebiten.Image) {
+func (g *Game) AddAsteroid(asteroidImage render.Image) *agents.Asteroid {
 	a := agents.NewAsteroid(g.log,
-		float64(rand.Intn(int(g.conf.ScreenWidth))),
-		float64(rand.Intn(int(g.conf.ScreenHeight/4))),
+		g.rand,
+		g.clock,
+		g.topology,
+		float64(g.rand.Intn(int(g.conf.ScreenWidth))),
+		float64(g.rand.Intn(int(g.conf.ScreenHeight/4))),
 		g.conf.ScreenWidth, g.conf.ScreenHeight,
 		g.Register, g.Unregister,
 		asteroidImage,
-		g.rubbleImages,
+		g.splitting,
 		g.debug)
 	g.Register(a)
+	return a
 }
 
-// AddAsteroid insert a new asteroid in the game.
-func (g *Game) AddBoid() {
+// AddBoid insert a new boid of a given species in the game.
+func (g *Game) AddBoid(species string) {
+	s := g.findSpecies(species)
+	if s == nil {
+		g.log.Errorf("unknown boid species %s", species)
+		return
+	}
+	if s.neural {
+		g.addNeuralBoid(s)
+		return
+	}
 	b := ai.NewBoid(g.log,
-		float64(rand.Intn(int(g.conf.ScreenWidth))),
-		float64(rand.Intn(int(g.conf.ScreenHeight/4))),
+		g.rand,
+		g.clock,
+		g.topology,
+		float64(g.rand.Intn(int(g.conf.ScreenWidth))),
+		float64(g.rand.Intn(int(g.conf.ScreenHeight/4))),
 		g.conf.ScreenWidth, g.conf.ScreenHeight,
-		g.boidImage,
+		g.Unregister,
+		s.image,
 		g.Vision,
+		s.flocking,
 		g.debug)
+	b.SetSize(s.size)
 	g.Register(b)
 }
 
-// RestartGame cleans current game and a start a new game.
-func (g *Game) RestartGame() {
-	for k := range g.starships {
-		delete(g.starships, k)
+// addNeuralBoid insert a new boid driven by the species brain in the game.
+func (g *Game) addNeuralBoid(s *boidSpecies) {
+	if s.brain == nil {
+		g.log.Errorf("no brain for %s boids", s.name)
+		return
+	}
+	b := ai.NewNeuralBoid(g.log,
+		g.rand,
+		g.clock,
+		g.topology,
+		float64(g.rand.Intn(int(g.conf.ScreenWidth))),
+		float64(g.rand.Intn(int(g.conf.ScreenHeight/4))),
+		g.conf.ScreenWidth, g.conf.ScreenHeight,
+		g.Unregister,
+		s.image,
+		g.Vision,
+		s.flocking,
+		s.brain,
+		g.debug)
+	b.SetSize(s.size)
+	g.Register(b)
+}
+
+// RespawnBoids refills every species which lost some boids, up to its population.
+func (g *Game) RespawnBoids() {
+	population := g.population()
+	for _, s := range g.species {
+		for i := population[s.name]; i < s.population; i++ {
+			g.AddBoid(s.name)
+		}
 	}
-	for k := range g.asteroids {
-		delete(g.asteroids, k)
+}
+
+// population returns the number of boids alive, per species.
+func (g *Game) population() map[string]int {
+	population := make(map[string]int)
+	for _, a := range g.agents.OfType(physics.BoidAgent) {
+		if boid, ok := a.(interface{ Species() string }); ok {
+			population[boid.Species()]++
+		}
 	}
-	for k := range g.bullets {
-		delete(g.bullets, k)
+	return population
+}
+
+// AddPredator insert a new predator in the game.
+func (g *Game) AddPredator() {
+	p := ai.NewPredator(g.log,
+		g.rand,
+		g.clock,
+		g.topology,
+		float64(g.rand.Intn(int(g.conf.ScreenWidth))),
+		float64(g.rand.Intn(int(g.conf.ScreenHeight/4))),
+		g.conf.ScreenWidth, g.conf.ScreenHeight,
+		g.Unregister,
+		g.predatorImage,
+		g.HuntVision,
+		g.predatorFlocking,
+		g.hunting,
+		g.debug)
+	g.Register(p)
+}
+
+// AddSaucer insert a new flying saucer, big or small, in the game.
+func (g *Game) AddSaucer(small bool) {
+	saucerImage := g.bigSaucerImage
+	if small {
+		saucerImage = g.smallSaucerImage
 	}
-	for k := range g.boids {
-		delete(g.boids, k)
+	s := agents.NewSaucer(g.log,
+		g.rand,
+		g.clock,
+		g.topology,
+		small,
+		g.conf.ScreenWidth, g.conf.ScreenHeight,
+		g.Register, g.Unregister,
+		g.SaucerVision,
+		saucerImage,
+		g.bulletImage,
+		g.debug)
+	g.Register(s)
+}
+
+// saucerDelay returns a random delay before the next flying saucer, saucerEvery seconds on average.
+func (g *Game) saucerDelay() time.Duration {
+	every := g.saucerEvery()
+	if every <= 0 {
+		return 0
+	}
+	return time.Duration(every * (0.5 + g.rand.Float64()) * float64(time.Second))
+}
+
+// saucerEvery returns the average time delay (in second) between two flying saucers, in the current level.
+func (g *Game) saucerEvery() float64 {
+	if levels := g.levels(); levels != nil {
+		return levels[g.level].SaucerEvery
 	}
+	return g.conf.Saucer.Every
+}
+
+// RestartGame cleans current game and a start a new game.
+func (g *Game) RestartGame() {
+	// agents are cleared without leaving the game one by one
+	sounds.Stop(sounds.SaucerBig)
+	sounds.Stop(sounds.SaucerSmall)
+	g.agents.Clear()
+	g.index.Clear()
 
 	g.StartGame()
 }
 
 // Vision returns all agents located in a radius from (x,y)
 func (g *Game) Vision(x, y float64) []physics.Physic {
-	nearestAgents := []physics.Physic{}
-	radius := g.conf.VisionRadius
+	return g.index.Query(x, y, g.visionRadius())
+}
 
-	for _, v := range g.starships {
-		if (v.Position().X-x)*(v.Position().X-x)+(v.Position().Y-y)*(v.Position().Y-y) < radius*radius {
-			nearestAgents = append(nearestAgents, v)
-		}
-	}
-	for _, v := range g.asteroids {
-		if (v.Position().X-x)*(v.Position().X-x)+(v.Position().Y-y)*(v.Position().Y-y) < radius*radius {
-			nearestAgents = append(nearestAgents, v)
+// visionRadius returns the radius of boids vision, wide enough for all flocking rules of all species.
+func (g *Game) visionRadius() float64 {
+	radius := g.conf.VisionRadius
+	for _, s := range g.species {
+		if r := s.flocking.MaxRadius(); r > radius {
+			radius = r
 		}
 	}
-	for _, v := range g.bullets {
-		if (v.Position().X-x)*(v.Position().X-x)+(v.Position().Y-y)*(v.Position().Y-y) < radius*radius {
-			nearestAgents = append(nearestAgents, v)
-		}
+	return radius
+}
+
+// MeasureFlocks measures the flocking metrics of boids, and publishes them.
+func (g *Game) MeasureFlocks() metrics.Sample {
+	boids := []metrics.Agent{}
+	for _, b := range g.agents.OfType(physics.BoidAgent) {
+		boids = append(boids, b)
 	}
-	for _, v := range g.boids {
-		if (v.Position().X-x)*(v.Position().X-x)+(v.Position().Y-y)*(v.Position().Y-y) < radius*radius {
-			nearestAgents = append(nearestAgents, v)
+	g.flocks = metrics.Measure(g.topology, boids, g.visionRadius())
+	g.flocks.Tick = g.clock.Ticks()
+	g.events.Publish(FlocksMeasured{Sample: g.flocks})
+	return g.flocks
+}
+
+// ExportMetrics exports the flocking metrics every time they are measured.
+func (g *Game) ExportMetrics(exporter metrics.Exporter) {
+	g.events.Subscribe(FlocksMeasuredKind, func(e Event) {
+		err := exporter.Export(e.(FlocksMeasured).Sample)
+		if err != nil {
+			g.log.Errorf("error when exporting metrics: %s", err.Error())
 		}
+	})
+}
+
+// SaucerVision returns the starships, wherever they are, for saucers to aim at them.
+func (g *Game) SaucerVision(x, y float64) []physics.Physic {
+	return g.agents.OfType(physics.StarshipAgent)
+}
+
+// HuntVision returns all agents located in the predators hunting radius from (x,y)
+func (g *Game) HuntVision(x, y float64) []physics.Physic {
+	return g.index.Query(x, y, g.conf.Predator.HuntRadius)
+}
+
+// newFlocking creates boids flocking parameters from configuration.
+func newFlocking(conf config.Flocking) *ai.Flocking {
+	return &ai.Flocking{
+		MaxSpeed:    conf.MaxSpeed,
+		MaxForce:    conf.MaxForce,
+		Separation:  ai.Rule{Radius: conf.Separation.Radius, Weight: conf.Separation.Weight},
+		Cohesion:    ai.Rule{Radius: conf.Cohesion.Radius, Weight: conf.Cohesion.Weight},
+		Alignment:   ai.Rule{Radius: conf.Alignment.Radius, Weight: conf.Alignment.Weight},
+		Avoidance:   ai.Rule{Radius: conf.Avoidance.Radius, Weight: conf.Avoidance.Weight},
+		Flee:        ai.Rule{Radius: conf.Flee.Radius, Weight: conf.Flee.Weight},
+		Repulsion:   ai.Rule{Radius: conf.Repulsion.Radius, Weight: conf.Repulsion.Weight},
+		Walls:       ai.Rule{Radius: conf.Walls.Radius, Weight: conf.Walls.Weight},
+		FieldOfView: conf.FieldOfView,
+		Occlusion:   conf.Occlusion,
 	}
+}
 
-	return nearestAgents
+// IndexAgents rebuilds the spatial index of the agents from their current position.
+func (g *Game) IndexAgents() {
+	g.index.Clear()
+	for _, a := range g.agents.All() {
+		g.index.Insert(a)
+	}
 }
 
 // Register adds a new agent (player or ai) to the game.
+// The agent is updated with the game physics integrator.
 func (g *Game) Register(agent physics.Physic) {
-	switch agent.Type() {
-	case physics.StarshipAgent:
-		g.starships[agent.ID()] = agent
-	case physics.AsteroidAgent:
-		g.asteroids[agent.ID()] = agent
-	case physics.RubbleAgent:
-		g.asteroids[agent.ID()] = agent
-	case physics.BulletAgent:
-		g.bullets[agent.ID()] = agent
-	case physics.BoidAgent:
-		g.boids[agent.ID()] = agent
-	default:
-	}
+	agent.SetIntegrator(g.integrator)
+	g.agents.Add(agent)
 }
 
 // Unregister deletes an agent (player or ai) from the game.
 func (g *Game) Unregister(id, agentType string) {
-	switch agentType {
-	case physics.StarshipAgent:
-		delete(g.starships, id)
-	case physics.AsteroidAgent:
-		delete(g.asteroids, id)
-	case physics.RubbleAgent:
-		delete(g.asteroids, id)
-	case physics.BulletAgent:
-		delete(g.bullets, id)
-	default:
-	}
+	g.agents.Remove(id)
+}
+
+// Events returns the game event bus, to subscribe to game events.
+func (g *Game) Events() *EventBus {
+	return g.events
+}
+
+// AgentsCount returns the number of agents in the game.
+func (g *Game) AgentsCount() int {
+	return g.agents.Len()
+}
+
+// Count returns the number of agents of the given types in the game.
+func (g *Game) Count(agentTypes ...string) int {
+	return g.agents.Count(agentTypes...)
+}
+
+// Lives returns the number of starships left, besides the one playing.
+func (g *Game) Lives() int {
+	return g.lives
+}
+
+// IsOver returns true when the game is over.
+func (g *Game) IsOver() bool {
+	return g.gameOver
 }
 
 // Layout takes the outside size (e.g., the window size) and returns the (logical) screen size.
@@ -0,0 +1,551 @@
+package game_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/agents"
+	"github.com/jtbonhomme/asteboids/internal/ai"
+	"github.com/jtbonhomme/asteboids/internal/ai/neural"
+	"github.com/jtbonhomme/asteboids/internal/clock"
+	"github.com/jtbonhomme/asteboids/internal/config"
+	"github.com/jtbonhomme/asteboids/internal/game"
+	"github.com/jtbonhomme/asteboids/internal/input"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func newTestConfig() *config.Config {
+	return &config.Config{
+		Asteroids:        4,
+		Boids:            20,
+		ScreenWidth:      1080,
+		ScreenHeight:     720,
+		ScoreTimeUnit:    5,
+		AsteroidsRespawn: 10,
+		MaxTPS:           60,
+		VisionRadius:     75,
+		Seed:             42,
+		Collisions:       config.DefaultCollisions(),
+		Restitution:      1,
+		Flocking:         config.DefaultFlocking(),
+		Asteroid:         config.DefaultAsteroid(),
+	}
+}
+
+func newTestLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(ioutil.Discard)
+	return log
+}
+
+func TestHeadless(t *testing.T) {
+	conf := newTestConfig()
+	g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, input.None{})
+	g.StartGame()
+
+	if g.AgentsCount() != 1+conf.Asteroids+conf.Boids {
+		t.Errorf("expected %d agents got %d", 1+conf.Asteroids+conf.Boids, g.AgentsCount())
+	}
+
+	for i := 0; i < 600; i++ {
+		err := g.Update()
+		if err != nil {
+			t.Fatalf("update %d failed: %s", i, err.Error())
+		}
+		g.Draw(render.NullScreen{})
+	}
+}
+
+// shooter is an input which keeps turning and shooting.
+type shooter struct{}
+
+func (shooter) IsKeyPressed(key input.Key) bool {
+	return key == input.KeySpace || key == input.KeyLeft
+}
+
+func runGame(conf *config.Config, ticks int) []byte {
+	g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, shooter{})
+	g.StartGame()
+	for i := 0; i < ticks; i++ {
+		_ = g.Update()
+	}
+	var buf bytes.Buffer
+	_ = g.DumpTo(&buf)
+	return buf.Bytes()
+}
+
+func TestDeterminism(t *testing.T) {
+	conf := newTestConfig()
+	first := runGame(conf, 1200)
+	second := runGame(conf, 1200)
+	if !bytes.Equal(first, second) {
+		t.Errorf("expected identical game states for seed %d", conf.Seed)
+	}
+
+	conf.Seed = 43
+	third := runGame(conf, 1200)
+	if bytes.Equal(first, third) {
+		t.Errorf("expected different game states for seeds 42 and 43")
+	}
+}
+
+func TestTickRateIndependence(t *testing.T) {
+	conf := newTestConfig()
+	reference := runGame(conf, 600)
+
+	// at twice the tick rate, the same physics steps run in twice more ticks
+	conf.MaxTPS = 120
+	if !bytes.Equal(reference, runGame(conf, 1200)) {
+		t.Errorf("expected identical game states at 60 and 120 TPS")
+	}
+
+	conf.MaxTPS = 30
+	if !bytes.Equal(reference, runGame(conf, 300)) {
+		t.Errorf("expected identical game states at 60 and 30 TPS")
+	}
+}
+
+func TestVisionAcrossEdges(t *testing.T) {
+	tests := []struct {
+		name     string
+		topology string
+		want     int
+	}{
+		{name: "toroidal", topology: "toroidal", want: 1},
+		{name: "bounded", topology: "bounded", want: 0},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conf := newTestConfig()
+			conf.Topology = tt.topology
+			g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, input.None{})
+			g.Register(agents.NewAsteroid(newTestLogger(), rand.New(rand.NewSource(1)), clock.New(60),
+				topology.Toroidal{Width: conf.ScreenWidth, Height: conf.ScreenHeight},
+				conf.ScreenWidth-5, conf.ScreenHeight/2, conf.ScreenWidth, conf.ScreenHeight,
+				g.Register, g.Unregister, nil, nil, false))
+			g.IndexAgents()
+
+			if got := len(g.Vision(5, conf.ScreenHeight/2)); got != tt.want {
+				t.Errorf("got %d agents seen across the edge, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpecies(t *testing.T) {
+	tests := []struct {
+		name    string
+		species []config.Species
+		want    int
+	}{
+		{name: "default species", want: 20},
+		{
+			name: "two species",
+			species: []config.Species{
+				{Name: "fish", Boids: 5, Color: "#3296c8"},
+				{Name: "shark", Boids: 3, Color: "#c83232", Size: 16, Affinity: map[string]float64{"fish": 1}},
+			},
+			want: 8,
+		},
+		{
+			name:    "invalid color",
+			species: []config.Species{{Name: "fish", Boids: 5, Color: "blue"}},
+			want:    5,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conf := newTestConfig()
+			conf.Species = tt.species
+			g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, input.None{})
+			g.StartGame()
+			if got := g.AgentsCount() - 1 - conf.Asteroids; got != tt.want {
+				t.Errorf("expected %d boids got %d", tt.want, got)
+			}
+			for i := 0; i < 60; i++ {
+				_ = g.Update()
+			}
+		})
+	}
+}
+
+// keys is an input whose pressed keys can be changed during the game.
+type keys map[input.Key]bool
+
+func (k keys) IsKeyPressed(key input.Key) bool {
+	return k[key]
+}
+
+func TestLives(t *testing.T) {
+	conf := newTestConfig()
+	conf.Asteroids = 0
+	conf.Boids = 0
+	conf.Lives = 2
+	conf.Invulnerability = 3
+	pressed := keys{input.KeyEscape: true}
+	g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, pressed)
+	g.StartGame()
+	if g.Lives() != 1 {
+		t.Fatalf("got %d lives, want 1", g.Lives())
+	}
+
+	// the starship self destroys, and respawns in the clear center
+	g.Step()
+	if g.IsOver() || g.Lives() != 0 || g.Count(physics.StarshipAgent) != 1 {
+		t.Fatalf("expected a starship to respawn, got game over %t, %d lives", g.IsOver(), g.Lives())
+	}
+
+	// the respawned starship is invulnerable
+	delete(pressed, input.KeyEscape)
+	collisions := 0
+	g.OnCollision(func(e game.CollisionEvent) {
+		collisions++
+	})
+	g.Register(agents.NewAsteroid(newTestLogger(), rand.New(rand.NewSource(1)), clock.New(60),
+		topology.Toroidal{Width: conf.ScreenWidth, Height: conf.ScreenHeight},
+		conf.ScreenWidth/2+10, conf.ScreenHeight/2, conf.ScreenWidth, conf.ScreenHeight,
+		g.Register, g.Unregister, nil, nil, false))
+	for i := 0; i < 10; i++ {
+		g.Step()
+	}
+	if collisions != 0 || g.IsOver() {
+		t.Errorf("expected an invulnerable starship, got %d collisions", collisions)
+	}
+
+	// no life is left
+	pressed[input.KeyEscape] = true
+	g.Step()
+	if !g.IsOver() {
+		t.Error("expected the game to be over")
+	}
+}
+
+func TestExtraLife(t *testing.T) {
+	conf := newTestConfig()
+	conf.Asteroids = 0
+	conf.Lives = 1
+	conf.ExtraLife = 1
+	g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, input.None{})
+	won := 0
+	g.Events().Subscribe(game.ExtraLifeWonKind, func(e game.Event) {
+		won++
+	})
+	g.StartGame()
+	// a point every 5 seconds
+	for i := 0; i < 11*60; i++ {
+		g.Step()
+	}
+	if won != 2 || g.Lives() != 2 {
+		t.Errorf("got %d extra lives won and %d lives, want 2", won, g.Lives())
+	}
+}
+
+func TestLevels(t *testing.T) {
+	tests := []struct {
+		name          string
+		levels        []config.Level
+		endless       bool
+		steps         int
+		wantAsteroids int
+		wantLevel     int
+		wantWon       bool
+	}{
+		{
+			name:          "endless",
+			levels:        config.DefaultLevels(),
+			endless:       true,
+			steps:         60,
+			wantAsteroids: 4,
+		},
+		{
+			name:          "no level",
+			levels:        []config.Level{},
+			steps:         60,
+			wantAsteroids: 4,
+		},
+		{
+			name:          "level asteroids",
+			levels:        []config.Level{{Asteroids: 2, AsteroidSpeed: 1.5, Win: config.SurviveWin, Goal: 100}},
+			steps:         60,
+			wantAsteroids: 2,
+			wantLevel:     1,
+		},
+		{
+			name: "intermission",
+			levels: []config.Level{
+				{Win: config.SurviveWin, Goal: 1},
+				{Win: config.SurviveWin, Goal: 1},
+			},
+			steps:     3 * 60,
+			wantLevel: 1,
+		},
+		{
+			name: "next level",
+			levels: []config.Level{
+				{Win: config.SurviveWin, Goal: 1},
+				{Asteroids: 1, Win: config.ScoreWin, Goal: 100},
+			},
+			steps:     5 * 60,
+			wantLevel: 2,
+		},
+		{
+			name: "final level won",
+			levels: []config.Level{
+				{Win: config.SurviveWin, Goal: 1},
+				{Win: config.ClearWin},
+			},
+			steps:     5 * 60,
+			wantLevel: 2,
+			wantWon:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conf := newTestConfig()
+			conf.Boids = 0
+			conf.Levels = tt.levels
+			conf.Endless = tt.endless
+			g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, input.None{})
+			won := false
+			g.Events().Subscribe(game.GameOverKind, func(e game.Event) {
+				won = e.(game.GameOver).Won
+			})
+			g.StartGame()
+			if got := g.Count(physics.AsteroidAgent); got != tt.wantAsteroids {
+				t.Errorf("got %d asteroids, want %d", got, tt.wantAsteroids)
+			}
+			for i := 0; i < tt.steps; i++ {
+				g.Step()
+			}
+			if g.Level() != tt.wantLevel {
+				t.Errorf("got level %d, want %d", g.Level(), tt.wantLevel)
+			}
+			if g.IsOver() != tt.wantWon || won != tt.wantWon {
+				t.Errorf("got game over %t (won %t), want won %t", g.IsOver(), won, tt.wantWon)
+			}
+		})
+	}
+}
+
+func TestAsteroidSplitting(t *testing.T) {
+	tests := []struct {
+		name       string
+		split      int
+		wantMedium int
+		wantSmall  int
+		wantScore  int
+	}{
+		{name: "no split", split: 0, wantScore: 2},
+		{name: "halves", split: 2, wantMedium: 2, wantSmall: 4, wantScore: 2 + 2*5 + 4*10},
+		{name: "thirds", split: 3, wantMedium: 3, wantSmall: 9, wantScore: 2 + 3*5 + 9*10},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conf := newTestConfig()
+			conf.Asteroids = 0
+			conf.Boids = 0
+			// a level game, so that no asteroid respawns
+			conf.Levels = []config.Level{{Win: config.ClearWin}}
+			g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, input.None{})
+
+			var medium []*agents.Asteroid
+			var small []*agents.Rubble
+			register := func(agent physics.Physic) {
+				switch a := agent.(type) {
+				case *agents.Asteroid:
+					medium = append(medium, a)
+				case *agents.Rubble:
+					small = append(small, a)
+				}
+				g.Register(agent)
+			}
+			large := agents.NewAsteroid(newTestLogger(), rand.New(rand.NewSource(1)), clock.New(60),
+				topology.Toroidal{Width: conf.ScreenWidth, Height: conf.ScreenHeight},
+				conf.ScreenWidth/2, conf.ScreenHeight/2, conf.ScreenWidth, conf.ScreenHeight,
+				register, g.Unregister, nil, &agents.Splitting{Count: tt.split, Spread: 1}, false)
+			g.Register(large)
+
+			large.Explode()
+			if len(medium) != tt.wantMedium || g.Count(physics.AsteroidAgent) != tt.wantMedium {
+				t.Fatalf("got %d medium asteroids, want %d", g.Count(physics.AsteroidAgent), tt.wantMedium)
+			}
+			for _, a := range medium {
+				speed := a.Velocity()
+				if a.Tier() != agents.MediumTier || speed.MagnitudeSquared() < 0.8*0.8 || speed.MagnitudeSquared() > 1.4*1.4+1e-9 {
+					t.Errorf("got a tier %d asteroid at speed %v, want a medium one", a.Tier(), speed)
+				}
+				a.Explode()
+			}
+			if g.Count(physics.AsteroidAgent) != 0 || g.Count(physics.RubbleAgent) != tt.wantSmall {
+				t.Errorf("got %d small asteroids, want %d", g.Count(physics.RubbleAgent), tt.wantSmall)
+			}
+			for _, r := range small {
+				r.Explode()
+			}
+			if g.Score() != tt.wantScore {
+				t.Errorf("got score %d, want %d", g.Score(), tt.wantScore)
+			}
+		})
+	}
+}
+
+func TestHyperspace(t *testing.T) {
+	tests := []struct {
+		name     string
+		failure  float64
+		wantShip bool
+	}{
+		{name: "jump", failure: 0, wantShip: true},
+		{name: "failure", failure: 1, wantShip: false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conf := newTestConfig()
+			conf.Asteroids = 0
+			conf.Boids = 0
+			conf.Hyperspace = config.Hyperspace{Cooldown: 5, Failure: tt.failure}
+			g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, keys{input.KeyH: true})
+			ship := g.AddStarship()
+			start := ship.Position()
+
+			g.Step()
+			if got := g.Count(physics.StarshipAgent) == 1; got != tt.wantShip {
+				t.Fatalf("got starship %t after the jump, want %t", got, tt.wantShip)
+			}
+			if !tt.wantShip {
+				return
+			}
+			jumped := ship.Position()
+			if jumped == start || ship.HyperspaceCooldown() <= 0 {
+				t.Errorf("expected the starship to jump from %v, got %v with cooldown %s", start, jumped, ship.HyperspaceCooldown())
+			}
+			// no jump before the cooldown is over
+			g.Step()
+			if ship.Position() != jumped {
+				t.Errorf("expected the starship to stay at %v, got %v", jumped, ship.Position())
+			}
+		})
+	}
+}
+
+func TestShield(t *testing.T) {
+	tests := []struct {
+		name       string
+		pressed    keys
+		steps      int
+		wantShield bool
+		wantShip   bool
+	}{
+		{name: "shield up", pressed: keys{input.KeyDown: true}, steps: 10, wantShield: true, wantShip: true},
+		{name: "shield down", pressed: keys{}, steps: 10},
+		// the shield drains in 2 seconds, and stays depleted while its key is held
+		{name: "shield drained", pressed: keys{input.KeyDown: true}, steps: 3 * 60},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			conf := newTestConfig()
+			conf.Asteroids = 0
+			conf.Boids = 0
+			conf.Shield = config.Shield{Drain: 0.5, Recharge: 0.1}
+			g := game.New(newTestLogger(), conf, &render.Headless{TPS: 60}, tt.pressed)
+			ship := g.AddStarship()
+			for i := 0; i < tt.steps; i++ {
+				g.Step()
+				if i >= tt.steps-2 && ship.Shielded() != tt.wantShield {
+					t.Fatalf("got shield %t at step %d (energy %f), want %t", ship.Shielded(), i, ship.Energy(), tt.wantShield)
+				}
+			}
+
+			g.Register(agents.NewAsteroid(newTestLogger(), rand.New(rand.NewSource(1)), clock.New(60),
+				topology.Toroidal{Width: conf.ScreenWidth, Height: conf.ScreenHeight},
+				ship.Position().X+10, ship.Position().Y, conf.ScreenWidth, conf.ScreenHeight,
+				g.Register, g.Unregister, nil, nil, false))
+			for i := 0; i < 10; i++ {
+				g.Step()
+			}
+			if got := g.Count(physics.StarshipAgent) == 1; got != tt.wantShip {
+				t.Fatalf("got starship %t, want %t", got, tt.wantShip)
+			}
+			if tt.wantShip && (ship.Energy() >= 1 || ship.Energy() <= 0) {
+				t.Errorf("expected the shield energy to drain, got %f", ship.Energy())
+			}
+		})
+	}
+}
+
+func TestNeuralBrain(t *testing.T) {
+	tests := []struct {
+		name       string
+		layers     []int
+		wantBoids  int
+		wantErrors int
+	}{
+		{name: "valid brain", layers: []int{ai.Sensors, 4, ai.Actions}, wantBoids: 5},
+		{name: "wrong inputs", layers: []int{3, 4, ai.Actions}, wantErrors: 1},
+		{name: "wrong outputs", layers: []int{ai.Sensors, 4, 1}, wantErrors: 1},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			brain := filepath.Join(t.TempDir(), "brain.json")
+			f, err := os.Create(brain)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := neural.New(tt.layers...).Save(f); err != nil {
+				t.Fatal(err)
+			}
+			f.Close()
+
+			conf := newTestConfig()
+			conf.Asteroids = 0
+			conf.Boids = 0
+			conf.Neural = config.Neural{Boids: 5, Brain: brain, Color: "#64c864"}
+			log, hook := logtest.NewNullLogger()
+			g := game.New(log, conf, &render.Headless{TPS: 60}, input.None{})
+			g.StartGame()
+			g.RespawnBoids()
+			if got := g.Count(physics.BoidAgent); got != tt.wantBoids {
+				t.Errorf("got %d neural boids, want %d", got, tt.wantBoids)
+			}
+			// an invalid brain is reported once, not on every boid respawn
+			errors := 0
+			for _, e := range hook.AllEntries() {
+				if e.Level == logrus.ErrorLevel {
+					errors++
+				}
+			}
+			if errors != tt.wantErrors {
+				t.Errorf("got %d errors logged, want %d", errors, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,98 @@
+package game
+
+import (
+	"time"
+
+	"github.com/jtbonhomme/asteboids/internal/config"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+)
+
+// intermissionDelay is the time between two levels, while the next level banner is shown.
+const intermissionDelay time.Duration = 3 * time.Second
+
+// levels returns the levels of the game, or nil for an endless game.
+// A game without any level is endless.
+func (g *Game) levels() []config.Level {
+	if g.conf.Endless || len(g.conf.Levels) == 0 {
+		return nil
+	}
+	return g.conf.Levels
+}
+
+// Level returns the number of the current level, from 1, or 0 in an endless game.
+func (g *Game) Level() int {
+	if g.levels() == nil {
+		return 0
+	}
+	return g.level + 1
+}
+
+// startLevel adds the asteroids of the current level, and tops up the boids of each species.
+func (g *Game) startLevel() {
+	l := g.levels()[g.level]
+	for i := 0; i < l.Asteroids; i++ {
+		a := g.AddAsteroid(g.asteroidImages[g.rand.Intn(5)])
+		if l.AsteroidSpeed > 0 {
+			a.SetSpeed(l.AsteroidSpeed)
+		}
+	}
+
+	population := g.population()
+	for _, s := range g.species {
+		s.population = s.boids
+		if l.Boids > 0 {
+			s.population = l.Boids
+		}
+		for i := population[s.name]; i < s.population; i++ {
+			g.AddBoid(s.name)
+		}
+	}
+
+	g.levelStart = g.clock.Now()
+	g.levelPoints = g.Score()
+	g.nextSaucer = g.clock.Now() + g.saucerDelay()
+	g.events.Publish(LevelStarted{Level: g.level + 1, Name: l.Name})
+}
+
+// updateLevel starts the next level once the current one is cleared, after an intermission.
+// Clearing the last level wins the game.
+func (g *Game) updateLevel() {
+	levels := g.levels()
+	if g.intermission {
+		if g.clock.Now() >= g.nextLevel {
+			g.intermission = false
+			g.level++
+			g.startLevel()
+		}
+		return
+	}
+	if !g.levelCleared(levels[g.level]) {
+		return
+	}
+
+	g.events.Publish(LevelCleared{Level: g.level + 1})
+	if g.level == len(levels)-1 {
+		g.gameOver = true
+		g.gameWon = true
+		g.events.Publish(GameOver{
+			Won:      g.gameWon,
+			Score:    g.Score(),
+			Duration: g.gameDuration,
+		})
+		return
+	}
+	g.intermission = true
+	g.nextLevel = g.clock.Now() + intermissionDelay
+}
+
+// levelCleared returns true once the level win condition is met.
+func (g *Game) levelCleared(l config.Level) bool {
+	switch l.Win {
+	case config.ScoreWin:
+		return g.Score()-g.levelPoints >= int(l.Goal)
+	case config.SurviveWin:
+		return g.clock.Since(g.levelStart) >= time.Duration(l.Goal*float64(time.Second))
+	default:
+		return g.agents.Count(physics.AsteroidAgent, physics.RubbleAgent) == 0
+	}
+}
@@ -0,0 +1,96 @@
+package game
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jtbonhomme/asteboids/internal/input"
+)
+
+// panelEntry is a value which can be tuned from the debug panel.
+type panelEntry struct {
+	name  string
+	value *float64
+	step  float64
+}
+
+// debugPanel lets tune values at runtime in debug mode:
+// Tab selects the next value, + and - increase or decrease it.
+type debugPanel struct {
+	title    string
+	entries  []panelEntry
+	selected int
+	pressed  map[input.Key]bool
+}
+
+// newFlockingPanel creates a debug panel for the flocking parameters of each boids species.
+func (g *Game) newFlockingPanel() *debugPanel {
+	entries := []panelEntry{}
+	for _, s := range g.species {
+		prefix := ""
+		if len(g.species) > 1 {
+			prefix = s.name + " "
+		}
+		f := s.flocking
+		entries = append(entries,
+			panelEntry{name: prefix + "max speed", value: &f.MaxSpeed, step: 0.1},
+			panelEntry{name: prefix + "max force", value: &f.MaxForce, step: 0.01},
+			panelEntry{name: prefix + "separation radius", value: &f.Separation.Radius, step: 5},
+			panelEntry{name: prefix + "separation weight", value: &f.Separation.Weight, step: 0.1},
+			panelEntry{name: prefix + "cohesion radius", value: &f.Cohesion.Radius, step: 5},
+			panelEntry{name: prefix + "cohesion weight", value: &f.Cohesion.Weight, step: 0.1},
+			panelEntry{name: prefix + "alignment radius", value: &f.Alignment.Radius, step: 5},
+			panelEntry{name: prefix + "alignment weight", value: &f.Alignment.Weight, step: 0.1},
+			panelEntry{name: prefix + "avoidance look-ahead", value: &f.Avoidance.Radius, step: 5},
+			panelEntry{name: prefix + "avoidance weight", value: &f.Avoidance.Weight, step: 0.1},
+			panelEntry{name: prefix + "flee radius", value: &f.Flee.Radius, step: 5},
+			panelEntry{name: prefix + "flee weight", value: &f.Flee.Weight, step: 0.1},
+			panelEntry{name: prefix + "repulsion radius", value: &f.Repulsion.Radius, step: 5},
+			panelEntry{name: prefix + "repulsion weight", value: &f.Repulsion.Weight, step: 0.1},
+			panelEntry{name: prefix + "walls margin", value: &f.Walls.Radius, step: 5},
+			panelEntry{name: prefix + "walls weight", value: &f.Walls.Weight, step: 0.1},
+			panelEntry{name: prefix + "field of view", value: &f.FieldOfView, step: 10},
+		)
+	}
+	return &debugPanel{
+		title:   "Flocking",
+		entries: entries,
+		pressed: make(map[input.Key]bool),
+	}
+}
+
+// justPressed returns true the first tick a key is pressed.
+func (p *debugPanel) justPressed(in input.Input, key input.Key) bool {
+	pressed := in.IsKeyPressed(key)
+	wasPressed := p.pressed[key]
+	p.pressed[key] = pressed
+	return pressed && !wasPressed
+}
+
+// Update reads the panel commands.
+func (p *debugPanel) Update(in input.Input) {
+	if p.justPressed(in, input.KeyTab) {
+		p.selected = (p.selected + 1) % len(p.entries)
+	}
+	entry := p.entries[p.selected]
+	if p.justPressed(in, input.KeyPlus) {
+		*entry.value += entry.step
+	}
+	if p.justPressed(in, input.KeyMinus) && *entry.value-entry.step >= 0 {
+		*entry.value -= entry.step
+	}
+}
+
+// String returns the panel content, the selected value marked with an arrow.
+func (p *debugPanel) String() string {
+	var b strings.Builder
+	b.WriteString(p.title + " (tab, +, -)\n")
+	for i, entry := range p.entries {
+		marker := "  "
+		if i == p.selected {
+			marker = "> "
+		}
+		b.WriteString(fmt.Sprintf("%s%s: %0.2f\n", marker, entry.name, *entry.value))
+	}
+	return b.String()
+}
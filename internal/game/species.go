@@ -0,0 +1,114 @@
+package game
+
+import (
+	"fmt"
+	"image/color"
+
+	"github.com/jtbonhomme/asteboids/internal/ai"
+	"github.com/jtbonhomme/asteboids/internal/ai/neural"
+	"github.com/jtbonhomme/asteboids/internal/config"
+	"github.com/jtbonhomme/asteboids/internal/images"
+	"github.com/jtbonhomme/asteboids/internal/render"
+)
+
+// neuralSpecies is the name of the species of boids driven by a neural network.
+const neuralSpecies string = "neural"
+
+// boidSpecies holds what the boids of a species share.
+type boidSpecies struct {
+	name  string
+	boids int
+	size  float64
+	// population is the number of boids the species respawns up to
+	population int
+	flocking   *ai.Flocking
+	image      render.Image
+	// neural species boids are driven by a brain
+	neural bool
+	brain  ai.Brain
+}
+
+// newSpecies creates a boids species from configuration.
+// Unset flocking parameters are read from the flocking section.
+func (g *Game) newSpecies(conf config.Species) *boidSpecies {
+	defaults := config.DefaultSpecies(conf.Boids)
+	if conf.Name == "" {
+		conf.Name = defaults.Name
+	}
+	if conf.Size <= 0 {
+		conf.Size = defaults.Size
+	}
+	if conf.Color == "" {
+		conf.Color = defaults.Color
+	}
+	clr, err := parseColor(conf.Color)
+	if err != nil {
+		g.log.Errorf("error when reading %s species color: %s", conf.Name, err.Error())
+		clr, _ = parseColor(defaults.Color)
+	}
+	flocking := newFlocking(conf.Flocking.Inherit(g.conf.Flocking))
+	flocking.Species = conf.Name
+	flocking.Affinity = conf.Affinity
+	return &boidSpecies{
+		name:     conf.Name,
+		boids:    conf.Boids,
+		size:     conf.Size,
+		flocking: flocking,
+		image:    g.renderer.NewImage(images.Boid(int(conf.Size), int(conf.Size), clr)),
+	}
+}
+
+// newNeuralSpecies creates the species of boids driven by a neural network.
+// The network is read from the configured brain file, if any.
+// It returns nil if the brain file is invalid, and the game has no neural boids.
+func (g *Game) newNeuralSpecies() *boidSpecies {
+	s := g.newSpecies(config.Species{
+		Name:  neuralSpecies,
+		Boids: g.conf.Neural.Boids,
+		Color: g.conf.Neural.Color,
+	})
+	s.neural = true
+	if g.conf.Neural.Brain != "" {
+		brain, err := neural.LoadFile(g.conf.Neural.Brain)
+		switch {
+		case err != nil:
+			g.log.Errorf("error when loading neural boids brain, no neural boids: %s", err.Error())
+			return nil
+		case brain.Layers[0] != ai.Sensors || brain.Layers[len(brain.Layers)-1] != ai.Actions:
+			g.log.Errorf("error when loading neural boids brain, no neural boids: layers %v, want %d inputs and %d outputs",
+				brain.Layers, ai.Sensors, ai.Actions)
+			return nil
+		}
+		s.brain = brain
+	}
+	return s
+}
+
+// SetBrain sets the brain driving the neural boids added next.
+func (g *Game) SetBrain(brain ai.Brain) {
+	for _, s := range g.species {
+		if s.neural {
+			s.brain = brain
+		}
+	}
+}
+
+// findSpecies returns the boids species of a given name, or nil.
+func (g *Game) findSpecies(name string) *boidSpecies {
+	for _, s := range g.species {
+		if s.name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// parseColor reads a #rrggbb color.
+func parseColor(s string) (color.RGBA, error) {
+	clr := color.RGBA{A: 0xff}
+	_, err := fmt.Sscanf(s, "#%02x%02x%02x", &clr.R, &clr.G, &clr.B)
+	if err != nil {
+		return clr, fmt.Errorf("invalid color %s, expected #rrggbb", s)
+	}
+	return clr, nil
+}
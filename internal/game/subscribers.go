@@ -0,0 +1,176 @@
+package game
+
+import (
+	"github.com/jtbonhomme/asteboids/internal/agents"
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/sounds"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+// subscribeScoring counts points for every destroyed asteroid or rubble, depending on its size,
+// and every boid or saucer shot.
+func (g *Game) subscribeScoring() {
+	g.events.Subscribe(AsteroidDestroyedKind, func(e Event) {
+		g.points += g.asteroidScore(e.(AsteroidDestroyed).Asteroid)
+	})
+	g.events.Subscribe(RubbleDestroyedKind, func(e Event) {
+		g.points += g.asteroidScore(e.(RubbleDestroyed).Rubble)
+	})
+	g.events.Subscribe(CollisionEventKind, func(e Event) {
+		c := e.(CollisionEvent)
+		if c.B.Type() != physics.BulletAgent {
+			return
+		}
+		switch c.A.Type() {
+		case physics.BoidAgent:
+			g.points += g.conf.BoidScore
+		case physics.SaucerAgent:
+			if isSmallSaucer(c.A) {
+				g.points += g.conf.Saucer.SmallScore
+			} else {
+				g.points += g.conf.Saucer.BigScore
+			}
+		}
+	})
+}
+
+// subscribePanic scares the boids around every destroyed boid.
+func (g *Game) subscribePanic() {
+	g.events.Subscribe(BoidDestroyedKind, func(e Event) {
+		dead := e.(BoidDestroyed).Boid
+		for _, agent := range g.index.Query(dead.Position().X, dead.Position().Y, g.conf.Panic.Radius) {
+			if agent.Type() != physics.BoidAgent || agent.ID() == dead.ID() {
+				continue
+			}
+			if boid, ok := agent.(interface {
+				Scare(threat vector.Vector2D, force, decay float64)
+			}); ok {
+				boid.Scare(dead.Position(), g.conf.Panic.Force, g.conf.Panic.Decay)
+			}
+		}
+	})
+}
+
+// subscribeRespawn adds a new asteroid for every destroyed large asteroid, in an endless game.
+func (g *Game) subscribeRespawn() {
+	g.events.Subscribe(AsteroidDestroyedKind, func(e Event) {
+		if g.levels() == nil && asteroidTier(e.(AsteroidDestroyed).Asteroid) == agents.LargeTier {
+			g.AddAsteroid(g.asteroidImages[g.rand.Intn(5)])
+		}
+	})
+}
+
+// subscribeAudio plays a sound for game events.
+func (g *Game) subscribeAudio() {
+	play := func(s sounds.Sound) EventHandler {
+		return func(Event) {
+			sounds.Play(s)
+		}
+	}
+	g.events.Subscribe(AsteroidDestroyedKind, func(e Event) {
+		sounds.Play(bangSound(e.(AsteroidDestroyed).Asteroid))
+	})
+	g.events.Subscribe(RubbleDestroyedKind, play(sounds.BangSmall))
+	g.events.Subscribe(BoidDestroyedKind, play(sounds.BangSmall))
+	g.events.Subscribe(ShipDestroyedKind, play(sounds.BangLarge))
+	g.events.Subscribe(BulletFiredKind, play(sounds.Fire))
+	g.events.Subscribe(ShipThrustedKind, play(sounds.Thrust))
+	g.events.Subscribe(ExtraLifeWonKind, play(sounds.ExtraShip))
+
+	// saucers sound in a loop while any of their size is alive
+	g.events.Subscribe(SaucerSpawnedKind, func(e Event) {
+		sounds.Loop(saucerSound(e.(SaucerSpawned).Saucer))
+	})
+	g.events.Subscribe(SaucerDestroyedKind, func(e Event) {
+		destroyed := e.(SaucerDestroyed).Saucer
+		for _, s := range g.agents.OfType(physics.SaucerAgent) {
+			if isSmallSaucer(s) == isSmallSaucer(destroyed) {
+				return
+			}
+		}
+		sounds.Stop(saucerSound(destroyed))
+	})
+}
+
+// subscribeLogging logs game events.
+func (g *Game) subscribeLogging() {
+	for _, kind := range []EventKind{
+		AsteroidDestroyedKind,
+		RubbleDestroyedKind,
+		ShipDestroyedKind,
+		BulletFiredKind,
+		BoidSpawnedKind,
+		BoidDestroyedKind,
+		ExtraLifeWonKind,
+		SaucerSpawnedKind,
+		SaucerDestroyedKind,
+		LevelClearedKind,
+	} {
+		g.events.Subscribe(kind, func(e Event) {
+			g.log.Debugf("event %s", e.Kind())
+		})
+	}
+	g.events.Subscribe(LevelStartedKind, func(e Event) {
+		level := e.(LevelStarted)
+		g.log.Infof("level %d started: %s", level.Level, level.Name)
+	})
+	g.events.Subscribe(GameOverKind, func(e Event) {
+		over := e.(GameOver)
+		g.log.Infof("game over (won %t), score %d in %s", over.Won, over.Score, over.Duration)
+	})
+}
+
+// asteroidTier returns the size tier of an asteroid or rubble.
+func asteroidTier(agent physics.Physic) agents.Tier {
+	if a, ok := agent.(interface{ Tier() agents.Tier }); ok {
+		return a.Tier()
+	}
+	if agent.Type() == physics.RubbleAgent {
+		return agents.SmallTier
+	}
+	return agents.LargeTier
+}
+
+// asteroidScore returns the points won for a destroyed asteroid or rubble, depending on its size.
+func (g *Game) asteroidScore(agent physics.Physic) int {
+	switch asteroidTier(agent) {
+	case agents.SmallTier:
+		return g.conf.Asteroid.SmallScore
+	case agents.MediumTier:
+		return g.conf.Asteroid.MediumScore
+	default:
+		return g.conf.Asteroid.LargeScore
+	}
+}
+
+// bangSound returns the explosion sound of an asteroid, depending on its size.
+func bangSound(agent physics.Physic) sounds.Sound {
+	switch asteroidTier(agent) {
+	case agents.SmallTier:
+		return sounds.BangSmall
+	case agents.MediumTier:
+		return sounds.BangMedium
+	default:
+		return sounds.BangLarge
+	}
+}
+
+// isSmallSaucer returns true for a small flying saucer.
+func isSmallSaucer(agent physics.Physic) bool {
+	s, ok := agent.(interface{ Small() bool })
+	return ok && s.Small()
+}
+
+// thrusting returns true if the agent is a starship accelerating.
+func thrusting(agent physics.Physic) bool {
+	s, ok := agent.(interface{ Thrusting() bool })
+	return ok && s.Thrusting()
+}
+
+// saucerSound returns the sound of a flying saucer, depending on its size.
+func saucerSound(saucer physics.Physic) sounds.Sound {
+	if isSmallSaucer(saucer) {
+		return sounds.SaucerSmall
+	}
+	return sounds.SaucerBig
+}
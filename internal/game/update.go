@@ -2,84 +2,65 @@ package game
 
 import (
 	"fmt"
-	"math/rand"
+	"io"
 	"os"
 	"time"
 
-	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jtbonhomme/asteboids/internal/input"
 	"github.com/jtbonhomme/asteboids/internal/physics"
 	"github.com/jtbonhomme/asteboids/internal/sounds"
 )
 
-// UpdateAgents loops over all game agents to update them
-func (g *Game) UpdateAgents() {
-	for _, b := range g.bullets {
-		b.Update()
-	}
-	for _, a := range g.asteroids {
-		a.Update()
-	}
-	for _, s := range g.starships {
-		s.Update()
-	}
-	for _, b := range g.boids {
-		b.Update()
-	}
+// respawnClearRadius is the distance (in pixels) from the center of the screen
+// within which no hazard must be left for a starship to respawn.
+const respawnClearRadius float64 = 150
+
+// maxElapsed is the longest time simulated by a single uncapped tick, so that a stalled
+// game does not run a burst of physics steps to catch up.
+const maxElapsed time.Duration = 250 * time.Millisecond
+
+// UpdateAgents loops over all game agents to update them, dt seconds later.
+// Agents are updated in a stable order, so that a game can be replayed identically.
+// Agents spawned or destroyed during the update join or leave the game afterwards.
+func (g *Game) UpdateAgents(dt float64) {
+	g.agents.Range(func(a physics.Physic) {
+		a.Update(dt)
+	}, physics.BulletAgent, physics.SaucerBulletAgent, physics.AsteroidAgent, physics.RubbleAgent, physics.StarshipAgent, physics.BoidAgent, physics.PredatorAgent, physics.SaucerAgent)
 }
 
 // Update proceeds the game state.
-// Update is called every tick (1/60 [s] by default).
+// Update is called every tick (1/maxTPS [s]). The world is stepped at the fixed
+// physics rate, so that the game speed does not depend on the tick rate: an
+// accumulator counts the time elapsed, in units of 1/(maxTPS*physicsTPS) [s]
+// to avoid rounding errors, and runs as many physics steps as fit in it.
+// With uncapped ticks (maxTPS <= 0), the accumulator counts the real time elapsed instead.
 func (g *Game) Update() error {
-	// detect starship collision with asteroids
-	for _, starship := range g.starships {
-		_, ok := starship.IntersectMultiple(g.asteroids)
-		if ok {
-			starship.Explode()
+	if g.conf.MaxTPS > 0 {
+		g.accumulator += g.clock.TPS()
+		for g.accumulator >= g.conf.MaxTPS {
+			g.accumulator -= g.conf.MaxTPS
+			g.Step()
 		}
+	} else {
+		g.stepElapsed()
 	}
 
-	// detect asteroid collision with bullet
-	for _, asteroid := range g.asteroids {
-		bID, ok := asteroid.IntersectMultiple(g.bullets)
-		if ok {
-			asteroid.Explode()
-			asteroidType := asteroid.Type()
-			delete(g.bullets, bID)
-			g.kills++
-			// Only add a new asteroids if the destroyed agent is also an asteroid (not a rubble)
-			if asteroidType == physics.AsteroidAgent {
-				g.AddAsteroid(g.asteroidImages[rand.Intn(5)])
-			}
-		}
+	if g.debug {
+		g.panel.Update(g.input)
 	}
 
-	// Update the agents
-	g.UpdateAgents()
-
-	// game ends when there is no starship left
-	if len(g.starships) == 0 {
-		g.gameOver = true
-		g.gameWon = false
-	}
-
-	// update time until game ends
-	if !g.gameOver {
-		g.gameDuration = time.Since(g.startTime).Round(time.Second)
+	if g.gameOver && g.input.IsKeyPressed(input.KeyEnter) {
+		g.RestartGame()
 	}
 
-	// periodically add new asteroids
-	if g.conf.AsteroidsRespawn > 0 && int(g.gameDuration.Seconds()/g.conf.AsteroidsRespawn) > len(g.asteroids) {
-		g.AddAsteroid(g.asteroidImages[rand.Intn(5)])
-	}
-
-	if ebiten.IsKeyPressed(ebiten.KeyD) {
+	if g.input.IsKeyPressed(input.KeyD) {
 		err := g.Dump()
 		if err != nil {
 			g.log.Errorf("can't dump: %s", err.Error())
 		}
 	}
 
-	if ebiten.IsKeyPressed(ebiten.KeyM) {
+	if g.input.IsKeyPressed(input.KeyM) {
 		if g.mute {
 			sounds.Unmute()
 			g.mute = false
@@ -92,6 +73,105 @@ func (g *Game) Update() error {
 	return nil
 }
 
+// stepElapsed runs as many physics steps as fit in the real time elapsed since the previous tick.
+func (g *Game) stepElapsed() {
+	now := time.Now()
+	if !g.lastUpdate.IsZero() {
+		g.elapsed += now.Sub(g.lastUpdate)
+		if g.elapsed > maxElapsed {
+			g.elapsed = maxElapsed
+		}
+	}
+	g.lastUpdate = now
+	step := time.Second / time.Duration(g.clock.TPS())
+	for g.elapsed >= step {
+		g.elapsed -= step
+		g.Step()
+	}
+}
+
+// Step advances the world by one physics step (1/physicsTPS [s]).
+func (g *Game) Step() {
+	g.clock.Tick()
+	g.IndexAgents()
+
+	// detect collisions between agents
+	g.DetectCollisions()
+
+	// Update the agents
+	g.UpdateAgents(g.clock.Step())
+	g.IndexAgents()
+	for _, s := range g.agents.OfType(physics.StarshipAgent) {
+		if thrusting(s) {
+			g.events.Publish(ShipThrusted{Ship: s})
+		}
+	}
+
+	// a new starship respawns once the center is clear, while lives are left
+	if !g.gameOver && g.lives > 0 && g.agents.Count(physics.StarshipAgent) == 0 && g.centerClear() {
+		g.lives--
+		s := g.AddStarship()
+		s.SetInvulnerable(time.Duration(g.conf.Invulnerability * float64(time.Second)))
+	}
+
+	// game ends when there is no starship nor life left
+	if !g.gameOver && g.lives == 0 && g.agents.Count(physics.StarshipAgent) == 0 {
+		g.gameOver = true
+		g.gameWon = false
+		g.events.Publish(GameOver{
+			Won:      g.gameWon,
+			Score:    g.Score(),
+			Duration: g.gameDuration,
+		})
+	}
+
+	// update time until game ends
+	if !g.gameOver {
+		g.gameDuration = g.clock.Now().Round(time.Second)
+	}
+
+	// win an extra life every conf.ExtraLife points
+	if !g.gameOver && g.conf.ExtraLife > 0 && g.Score() >= g.nextExtraLife {
+		g.nextExtraLife += g.conf.ExtraLife
+		g.lives++
+		g.events.Publish(ExtraLifeWon{Lives: g.lives})
+	}
+
+	// periodically measure the flocks
+	if g.conf.Metrics.Every > 0 && g.clock.Ticks()%int64(g.conf.Metrics.Every) == 0 {
+		g.MeasureFlocks()
+	}
+
+	// periodically respawn the boids killed
+	if g.conf.BoidsRespawn > 0 && g.clock.Now() >= g.nextBoidsRespawn {
+		g.nextBoidsRespawn = g.clock.Now() + time.Duration(g.conf.BoidsRespawn*float64(time.Second))
+		g.RespawnBoids()
+	}
+
+	// flying saucers show up from time to time
+	if !g.gameOver && !g.intermission && g.saucerEvery() > 0 && g.clock.Now() >= g.nextSaucer {
+		g.nextSaucer = g.clock.Now() + g.saucerDelay()
+		g.AddSaucer(g.rand.Float64() < g.conf.Saucer.Small)
+	}
+
+	// levels progress once their win condition is met
+	if !g.gameOver && g.levels() != nil {
+		g.updateLevel()
+	}
+
+	// periodically add new asteroids, in an endless game
+	if g.levels() == nil && g.conf.AsteroidsRespawn > 0 && int(g.gameDuration.Seconds()/g.conf.AsteroidsRespawn) > g.agents.Count(physics.AsteroidAgent, physics.RubbleAgent) {
+		g.AddAsteroid(g.asteroidImages[g.rand.Intn(5)])
+	}
+}
+
+// centerClear returns true when no hazard is around the center of the screen, where starships respawn.
+func (g *Game) centerClear() bool {
+	hazards := g.index.Query(g.conf.ScreenWidth/2, g.conf.ScreenHeight/2, respawnClearRadius,
+		physics.AsteroidAgent, physics.RubbleAgent, physics.PredatorAgent, physics.BulletAgent, physics.SaucerAgent, physics.SaucerBulletAgent)
+	return len(hazards) == 0
+}
+
 // Dump saves internal game state in a file.
 func (g *Game) Dump() error {
 	var err error
@@ -104,30 +184,21 @@ func (g *Game) Dump() error {
 		return err
 	}
 	defer f.Close()
-	for _, a := range g.starships {
-		err = a.Dump(f)
-		if err != nil {
-			return err
-		}
-	}
-	for _, a := range g.asteroids {
-		err = a.Dump(f)
-		if err != nil {
-			return err
-		}
-	}
-	for _, a := range g.bullets {
-		err = a.Dump(f)
-		if err != nil {
-			return err
-		}
+	err = g.DumpTo(f)
+	if err != nil {
+		return err
 	}
-	for _, a := range g.boids {
-		err = a.Dump(f)
+	g.log.Infof("Saved dump: %s", name)
+	return err
+}
+
+// DumpTo writes out internal game state.
+func (g *Game) DumpTo(w io.Writer) error {
+	for _, a := range g.agents.OfType(physics.StarshipAgent, physics.AsteroidAgent, physics.RubbleAgent, physics.BulletAgent, physics.BoidAgent, physics.PredatorAgent, physics.SaucerAgent, physics.SaucerBulletAgent) {
+		err := a.Dump(w)
 		if err != nil {
 			return err
 		}
 	}
-	g.log.Infof("Saved dump: %s", name)
-	return err
+	return nil
 }
@@ -6,9 +6,11 @@ import (
 	_ "embed"
 	"errors"
 	"image"
+	"image/color"
 	"os"
 
-	"github.com/hajimehoshi/ebiten/v2"
+	// anonymous import for png decoder
+	_ "image/png"
 )
 
 //go:embed asteroid0.png
@@ -26,6 +28,21 @@ var asteroid3PNG []byte
 //go:embed asteroid4.png
 var asteroid4PNG []byte
 
+//go:embed medium0.png
+var medium0PNG []byte
+
+//go:embed medium1.png
+var medium1PNG []byte
+
+//go:embed medium2.png
+var medium2PNG []byte
+
+//go:embed medium3.png
+var medium3PNG []byte
+
+//go:embed medium4.png
+var medium4PNG []byte
+
 //go:embed rubble0.png
 var rubble0PNG []byte
 
@@ -50,20 +67,8 @@ var bulletPNG []byte
 //go:embed boid.png
 var boidPNG []byte
 
-// loadImageFromSlice loads an image into an ebiten image.
-func loadImageFromImage(screenWidth, screenHeight int, rawImage image.Image) (*ebiten.Image, error) {
-	newImage := ebiten.NewImage(screenWidth, screenHeight)
-	newImageFromImage := ebiten.NewImageFromImage(rawImage)
-	if newImageFromImage == nil {
-		return newImage, errors.New("error when creating image from raw")
-	}
-	newImage.DrawImage(newImageFromImage, nil)
-	return newImage, nil
-
-}
-
-// LoadImageFromSlice loads an image into an ebiten image from a slice of bytes.
-func LoadImageFromSlice(screenWidth, screenHeight int, name string) (*ebiten.Image, error) {
+// LoadImageFromSlice decodes an embedded image from its name.
+func LoadImageFromSlice(name string) (image.Image, error) {
 	var data []byte
 	switch name {
 	case "boid.png":
@@ -82,6 +87,16 @@ func LoadImageFromSlice(screenWidth, screenHeight int, name string) (*ebiten.Ima
 		data = asteroid3PNG
 	case "asteroid4.png":
 		data = asteroid4PNG
+	case "medium0.png":
+		data = medium0PNG
+	case "medium1.png":
+		data = medium1PNG
+	case "medium2.png":
+		data = medium2PNG
+	case "medium3.png":
+		data = medium3PNG
+	case "medium4.png":
+		data = medium4PNG
 	case "rubble0.png":
 		data = rubble0PNG
 	case "rubble1.png":
@@ -101,11 +116,11 @@ func LoadImageFromSlice(screenWidth, screenHeight int, name string) (*ebiten.Ima
 	if err != nil {
 		return nil, errors.New("error when decoding image from file " + err.Error())
 	}
-	return loadImageFromImage(screenWidth, screenHeight, rawImage)
+	return rawImage, nil
 }
 
-// LoadImageFromFile loads an image into an ebiten image from a file.
-func LoadImageFromFile(screenWidth, screenHeight int, file string) (*ebiten.Image, error) {
+// LoadImageFromFile decodes an image from a file.
+func LoadImageFromFile(file string) (image.Image, error) {
 	f, err := os.Open(file)
 	if err != nil {
 		return nil, errors.New("error when opening file " + err.Error())
@@ -117,5 +132,55 @@ func LoadImageFromFile(screenWidth, screenHeight int, file string) (*ebiten.Imag
 		return nil, errors.New("error when decoding image from file " + err.Error())
 	}
 
-	return loadImageFromImage(screenWidth, screenHeight, rawImage)
+	return rawImage, nil
+}
+
+// Boid draws the arrow shape of a boid, filled with a color.
+func Boid(w, h int, clr color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	fw, fh := float64(w), float64(h)
+	// the arrow is made of two triangles sharing the (fw, fh/2) - (fw/2, fh/2) edge
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+			if inTriangle(px, py, 0, 0, fw, fh/2, fw/2, fh/2) ||
+				inTriangle(px, py, 0, fh, fw, fh/2, fw/2, fh/2) {
+				img.Set(x, y, clr)
+			}
+		}
+	}
+	return img
+}
+
+// Saucer draws the shape of a flying saucer, a dome over a flat hull, filled with a color.
+func Saucer(w, h int, clr color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	fw, fh := float64(w), float64(h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			px, py := float64(x)+0.5, float64(y)+0.5
+			hull := inEllipse(px, py, fw/2, fh*0.65, fw/2, fh*0.25)
+			dome := py < fh*0.5 && inEllipse(px, py, fw/2, fh*0.5, fw/4, fh*0.4)
+			if hull || dome {
+				img.Set(x, y, clr)
+			}
+		}
+	}
+	return img
+}
+
+// inEllipse returns true if (px, py) is inside the ellipse centered on (cx, cy), of radii rx and ry.
+func inEllipse(px, py, cx, cy, rx, ry float64) bool {
+	dx, dy := (px-cx)/rx, (py-cy)/ry
+	return dx*dx+dy*dy <= 1
+}
+
+// inTriangle returns true if (px, py) is inside the (x1, y1), (x2, y2), (x3, y3) triangle.
+func inTriangle(px, py, x1, y1, x2, y2, x3, y3 float64) bool {
+	d1 := (px-x2)*(y1-y2) - (x1-x2)*(py-y2)
+	d2 := (px-x3)*(y2-y3) - (x2-x3)*(py-y3)
+	d3 := (px-x1)*(y3-y1) - (x3-x1)*(py-y1)
+	hasNeg := d1 < 0 || d2 < 0 || d3 < 0
+	hasPos := d1 > 0 || d2 > 0 || d3 > 0
+	return !(hasNeg && hasPos)
 }
@@ -0,0 +1,34 @@
+package input
+
+// Key represents a keyboard key used by the game.
+type Key int
+
+const (
+	KeyUp Key = iota
+	KeyLeft
+	KeyRight
+	KeySpace
+	KeyEscape
+	KeyEnter
+	KeyD
+	KeyM
+	KeyTab
+	KeyPlus
+	KeyMinus
+	KeyDown
+	KeyH
+)
+
+// Input reports the player's commands.
+type Input interface {
+	// IsKeyPressed returns true if key is currently pressed.
+	IsKeyPressed(key Key) bool
+}
+
+// None is an Input with no key ever pressed.
+type None struct{}
+
+// IsKeyPressed always returns false.
+func (None) IsKeyPressed(Key) bool {
+	return false
+}
@@ -0,0 +1,126 @@
+package metrics
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// Formats supported by exporters.
+const (
+	CSVFormat  string = "csv"
+	JSONFormat string = "json"
+)
+
+// Exporter writes out a time series of samples.
+type Exporter interface {
+	// Export writes out a sample.
+	Export(s Sample) error
+	// Close ends the time series, and closes the underlying writer.
+	Close() error
+}
+
+// Create creates a file exporting samples in a given format.
+func Create(path, format string) (Exporter, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	e, err := NewExporter(f, format)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return e, nil
+}
+
+// NewExporter creates an exporter writing samples in a given format.
+func NewExporter(w io.WriteCloser, format string) (Exporter, error) {
+	switch format {
+	case CSVFormat, "":
+		return &csvExporter{closer: w, w: csv.NewWriter(w)}, nil
+	case JSONFormat:
+		return &jsonExporter{w: w}, nil
+	default:
+		return nil, fmt.Errorf("unknown metrics format %s", format)
+	}
+}
+
+// csvExporter writes samples as CSV records, after a header.
+type csvExporter struct {
+	closer io.Closer
+	w      *csv.Writer
+	header bool
+}
+
+// Export writes out a sample as a CSV record.
+func (e *csvExporter) Export(s Sample) error {
+	if !e.header {
+		e.header = true
+		err := e.w.Write([]string{"tick", "boids", "polarization", "nearestNeighbour", "angularMomentum", "clusters"})
+		if err != nil {
+			return err
+		}
+	}
+	err := e.w.Write([]string{
+		strconv.FormatInt(s.Tick, 10),
+		strconv.Itoa(s.Boids),
+		strconv.FormatFloat(s.Polarization, 'f', 4, 64),
+		strconv.FormatFloat(s.NearestNeighbour, 'f', 4, 64),
+		strconv.FormatFloat(s.AngularMomentum, 'f', 4, 64),
+		strconv.Itoa(s.Clusters),
+	})
+	if err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// Close flushes the CSV records and closes the underlying writer.
+func (e *csvExporter) Close() error {
+	e.w.Flush()
+	if err := e.w.Error(); err != nil {
+		e.closer.Close()
+		return err
+	}
+	return e.closer.Close()
+}
+
+// jsonExporter writes samples as a JSON array.
+type jsonExporter struct {
+	w     io.WriteCloser
+	count int
+}
+
+// Export writes out a sample as a JSON object of the array.
+func (e *jsonExporter) Export(s Sample) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	prefix := ",\n"
+	if e.count == 0 {
+		prefix = "[\n"
+	}
+	e.count++
+	_, err = e.w.Write(append([]byte(prefix), b...))
+	return err
+}
+
+// Close ends the JSON array and closes the underlying writer.
+func (e *jsonExporter) Close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, err := e.w.Write([]byte(end))
+	if err != nil {
+		e.w.Close()
+		return err
+	}
+	return e.w.Close()
+}
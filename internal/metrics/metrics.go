@@ -0,0 +1,153 @@
+// Package metrics measures the emergent behaviour of flocks.
+package metrics
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+// Agent is a flock member, such as a physics.Physic.
+type Agent interface {
+	Position() vector.Vector2D
+	Velocity() vector.Vector2D
+}
+
+// Sample holds the flocking metrics measured at a given tick.
+type Sample struct {
+	// Tick is the physics step of the measure.
+	Tick int64 `json:"tick"`
+	// Boids is the number of flock members.
+	Boids int `json:"boids"`
+	// Polarization is the order parameter, from 0 (random headings) to 1 (all boids aligned).
+	Polarization float64 `json:"polarization"`
+	// NearestNeighbour is the average distance (in pixels) from a boid to its nearest neighbour.
+	NearestNeighbour float64 `json:"nearestNeighbour"`
+	// AngularMomentum is the milling parameter, from 0 (no rotation) to 1 (all boids turning around their center).
+	AngularMomentum float64 `json:"angularMomentum"`
+	// Clusters is the number of flocks, boids closer than the vision radius belonging to the same flock.
+	Clusters int `json:"clusters"`
+}
+
+// String displays the sample as a string.
+func (s Sample) String() string {
+	return fmt.Sprintf("Polarization: %0.2f\nNearest neighbour: %0.1f\nAngular momentum: %0.2f\nFlocks: %d",
+		s.Polarization, s.NearestNeighbour, s.AngularMomentum, s.Clusters)
+}
+
+// Measure computes the flocking metrics of agents, living in a given topology.
+// radius is the vision radius, within which agents belong to the same flock.
+func Measure(t topology.Topology, agents []Agent, radius float64) Sample {
+	return Sample{
+		Boids:            len(agents),
+		Polarization:     Polarization(agents),
+		NearestNeighbour: NearestNeighbour(t, agents),
+		AngularMomentum:  AngularMomentum(t, agents),
+		Clusters:         Clusters(t, agents, radius),
+	}
+}
+
+// Polarization returns the norm of the average heading of the moving agents.
+func Polarization(agents []Agent) float64 {
+	sum := vector.Vector2D{}
+	n := 0
+	for _, a := range agents {
+		v := a.Velocity()
+		if v.IsNil() {
+			continue
+		}
+		v.Normalize()
+		sum.Add(v)
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return math.Sqrt(sum.MagnitudeSquared()) / float64(n)
+}
+
+// NearestNeighbour returns the average distance from an agent to its nearest neighbour.
+func NearestNeighbour(t topology.Topology, agents []Agent) float64 {
+	if len(agents) < 2 {
+		return 0
+	}
+	sum := 0.0
+	for i, a := range agents {
+		nearest := math.Inf(1)
+		for j, b := range agents {
+			if i == j {
+				continue
+			}
+			if d := t.Distance(a.Position(), b.Position()); d < nearest {
+				nearest = d
+			}
+		}
+		sum += nearest
+	}
+	return sum / float64(len(agents))
+}
+
+// AngularMomentum returns the norm of the average angular momentum of the moving agents,
+// around their center, with unit distance and velocity.
+func AngularMomentum(t topology.Topology, agents []Agent) float64 {
+	if len(agents) == 0 {
+		return 0
+	}
+	// the center is computed from displacements, so that a flock spread across the world edges keeps a single center
+	origin := agents[0].Position()
+	center := vector.Vector2D{}
+	for _, a := range agents {
+		center.Add(t.Delta(origin, a.Position()))
+	}
+	center.Divide(float64(len(agents)))
+	center.Add(origin)
+
+	sum := 0.0
+	n := 0
+	for _, a := range agents {
+		r := t.Delta(center, a.Position())
+		v := a.Velocity()
+		if r.IsNil() || v.IsNil() {
+			continue
+		}
+		r.Normalize()
+		v.Normalize()
+		sum += r.X*v.Y - r.Y*v.X
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return math.Abs(sum) / float64(n)
+}
+
+// Clusters returns the number of connected components of the graph linking agents closer than radius.
+func Clusters(t topology.Topology, agents []Agent, radius float64) int {
+	parent := make([]int, len(agents))
+	for i := range parent {
+		parent[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		for parent[i] != i {
+			parent[i] = parent[parent[i]]
+			i = parent[i]
+		}
+		return i
+	}
+	clusters := len(agents)
+	for i := range agents {
+		for j := i + 1; j < len(agents); j++ {
+			if t.Distance(agents[i].Position(), agents[j].Position()) >= radius {
+				continue
+			}
+			if ri, rj := root(i), root(j); ri != rj {
+				parent[rj] = ri
+				clusters--
+			}
+		}
+	}
+	return clusters
+}
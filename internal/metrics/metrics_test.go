@@ -0,0 +1,149 @@
+package metrics_test
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/metrics"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+type agent struct {
+	position vector.Vector2D
+	velocity vector.Vector2D
+}
+
+func (a agent) Position() vector.Vector2D { return a.position }
+func (a agent) Velocity() vector.Vector2D { return a.velocity }
+
+func TestMeasure(t *testing.T) {
+	world := topology.Toroidal{Width: 800, Height: 600}
+
+	tests := []struct {
+		name   string
+		agents []metrics.Agent
+		want   metrics.Sample
+	}{
+		{name: "no agent", want: metrics.Sample{}},
+		{
+			name: "aligned flock",
+			agents: []metrics.Agent{
+				agent{vector.Vector2D{X: 100, Y: 100}, vector.Vector2D{Y: 3}},
+				agent{vector.Vector2D{X: 110, Y: 100}, vector.Vector2D{Y: 2}},
+				agent{vector.Vector2D{X: 120, Y: 100}, vector.Vector2D{Y: 1}},
+			},
+			want: metrics.Sample{Boids: 3, Polarization: 1, NearestNeighbour: 10, AngularMomentum: 0, Clusters: 1},
+		},
+		{
+			name: "opposite headings",
+			agents: []metrics.Agent{
+				agent{vector.Vector2D{X: 100, Y: 100}, vector.Vector2D{X: 3}},
+				agent{vector.Vector2D{X: 300, Y: 100}, vector.Vector2D{X: -3}},
+			},
+			want: metrics.Sample{Boids: 2, Polarization: 0, NearestNeighbour: 200, AngularMomentum: 0, Clusters: 2},
+		},
+		{
+			name: "milling",
+			agents: []metrics.Agent{
+				agent{vector.Vector2D{X: 110, Y: 100}, vector.Vector2D{Y: 1}},
+				agent{vector.Vector2D{X: 100, Y: 110}, vector.Vector2D{X: -1}},
+				agent{vector.Vector2D{X: 90, Y: 100}, vector.Vector2D{Y: -1}},
+				agent{vector.Vector2D{X: 100, Y: 90}, vector.Vector2D{X: 1}},
+			},
+			want: metrics.Sample{Boids: 4, Polarization: 0, NearestNeighbour: math.Sqrt(200), AngularMomentum: 1, Clusters: 1},
+		},
+		{
+			name: "flock across the edge",
+			agents: []metrics.Agent{
+				agent{vector.Vector2D{X: 795, Y: 100}, vector.Vector2D{X: 3}},
+				agent{vector.Vector2D{X: 5, Y: 100}, vector.Vector2D{X: 3}},
+			},
+			want: metrics.Sample{Boids: 2, Polarization: 1, NearestNeighbour: 10, AngularMomentum: 0, Clusters: 1},
+		},
+	}
+
+	const epsilon = 1e-9
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := metrics.Measure(world, tt.agents, 50)
+			if got.Boids != tt.want.Boids ||
+				got.Clusters != tt.want.Clusters ||
+				math.Abs(got.Polarization-tt.want.Polarization) > epsilon ||
+				math.Abs(got.NearestNeighbour-tt.want.NearestNeighbour) > epsilon ||
+				math.Abs(got.AngularMomentum-tt.want.AngularMomentum) > epsilon {
+				t.Errorf("Measure() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// buffer is a bytes.Buffer which can be closed.
+type buffer struct {
+	bytes.Buffer
+}
+
+func (b *buffer) Close() error { return nil }
+
+func TestExporter(t *testing.T) {
+	samples := []metrics.Sample{
+		{Tick: 60, Boids: 2, Polarization: 1, NearestNeighbour: 10, Clusters: 1},
+		{Tick: 120, Boids: 2, Polarization: 0.5, NearestNeighbour: 12.5, AngularMomentum: 0.25, Clusters: 2},
+	}
+
+	tests := []struct {
+		name    string
+		format  string
+		samples []metrics.Sample
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "csv",
+			format:  metrics.CSVFormat,
+			samples: samples,
+			want: "tick,boids,polarization,nearestNeighbour,angularMomentum,clusters\n" +
+				"60,2,1.0000,10.0000,0.0000,1\n" +
+				"120,2,0.5000,12.5000,0.2500,2\n",
+		},
+		{
+			name:    "json",
+			format:  metrics.JSONFormat,
+			samples: samples,
+			want: "[\n" +
+				`{"tick":60,"boids":2,"polarization":1,"nearestNeighbour":10,"angularMomentum":0,"clusters":1},` + "\n" +
+				`{"tick":120,"boids":2,"polarization":0.5,"nearestNeighbour":12.5,"angularMomentum":0.25,"clusters":2}` + "\n]\n",
+		},
+		{name: "empty json", format: metrics.JSONFormat, want: "[]\n"},
+		{name: "unknown format", format: "xml", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var b buffer
+			e, err := metrics.NewExporter(&b, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewExporter(%s) error = %v, wantErr %t", tt.format, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			for _, s := range tt.samples {
+				if err := e.Export(s); err != nil {
+					t.Fatalf("Export() error = %v", err)
+				}
+			}
+			if err := e.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+}
@@ -2,11 +2,13 @@ package physics
 
 import (
 	"image/color"
+	"math/rand"
 
 	"github.com/google/uuid"
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/jtbonhomme/asteboids/internal/clock"
 	"github.com/jtbonhomme/asteboids/internal/fonts"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/jtbonhomme/asteboids/internal/topology"
 	"github.com/jtbonhomme/asteboids/internal/vector"
 	"github.com/sirupsen/logrus"
 )
@@ -14,53 +16,66 @@ import (
 type Body struct {
 	position    vector.Vector2D
 	AgentType   string
-	id          uuid.UUID
+	id          string
 	Log         *logrus.Logger
+	Rand        *rand.Rand
+	Clock       *clock.Clock
+	Topology    topology.Topology
+	Integrator  Integrator
 	Orientation float64 // theta (radian)
 
 	PhysicWidth  float64
 	PhysicHeight float64
+	Shape        Shape
 	ScreenWidth  float64
 	ScreenHeight float64
 
-	velocity     vector.Vector2D
-	maxVelocity  float64
-	acceleration vector.Vector2D
+	velocity        vector.Vector2D
+	maxVelocity     float64
+	acceleration    vector.Vector2D
+	mass            float64
+	angularVelocity float64 // radian per 1/60 [s]
 
 	Register   AgentRegister
 	Unregister AgentUnregister
 	Vision     AgentVision
-	Image      *ebiten.Image
+	Image      render.Image
 
 	Debug bool
 }
 
-// Init initializes the physic body
+// Init initializes the physic body.
+// The body ID is drawn from the body random generator, if any.
 func (pb *Body) Init(velocity vector.Vector2D) {
-	pb.id = uuid.New()
+	id := uuid.New()
+	if pb.Rand != nil {
+		id, _ = uuid.NewRandomFromReader(pb.Rand)
+	}
+	pb.id = id.String()
 	pb.velocity = velocity
 	pb.maxVelocity = defaultMaxVelocity
+	pb.mass = defaultMass
 }
 
 // Draw draws the agent.
 // Draw is called every frame (typically 1/60[s] for 60Hz display).
-func (pb *Body) Draw(screen *ebiten.Image) {
-	op := &ebiten.DrawImageOptions{}
-	defer screen.DrawImage(pb.Image, op)
-
-	op.GeoM.Translate(-pb.PhysicWidth/2, -pb.PhysicHeight/2)
-	op.GeoM.Rotate(pb.Orientation)
-	op.GeoM.Translate(pb.position.X, pb.position.Y)
+func (pb *Body) Draw(screen render.Screen) {
 	if pb.Debug {
-		pb.DrawBodyBoundaryBox(screen)
+		pb.DrawShape(screen)
 		msg := pb.String()
-		textDim := text.BoundString(fonts.MonoSansRegularFont, msg)
+		textDim := screen.BoundString(fonts.MonoSansRegularFont, msg)
 		textWidth := textDim.Max.X - textDim.Min.X
-		text.Draw(screen,
+		screen.DrawText(
 			msg,
 			fonts.MonoSansRegularFont,
 			int(pb.Position().X)-textWidth/2,
 			int(pb.Position().Y+pb.PhysicHeight/2+5),
 			color.Gray16{0x999f})
 	}
+
+	geoM := render.GeoM{}
+	geoM.Translate(-pb.PhysicWidth/2, -pb.PhysicHeight/2)
+	geoM.Rotate(pb.Orientation)
+	geoM.Translate(pb.position.X, pb.position.Y)
+	screen.DrawImage(pb.Image, geoM)
 }
@@ -0,0 +1,65 @@
+package physics
+
+import "fmt"
+
+// ReferenceTPS is the tick rate physical quantities are expressed in:
+// velocities are in pixels per 1/60 [s], and accelerations in pixels per (1/60 [s])².
+const ReferenceTPS float64 = 60
+
+// Integrator names, to be used in configuration.
+const (
+	EulerIntegrator  string = "euler"
+	VerletIntegrator string = "verlet"
+)
+
+// Integrator advances a body velocity and position over a time step.
+type Integrator interface {
+	// Integrate advances the body by dt seconds.
+	Integrate(pb *Body, dt float64)
+}
+
+// NewIntegrator returns the integrator of a given name.
+func NewIntegrator(name string) (Integrator, error) {
+	switch name {
+	case EulerIntegrator, "":
+		return SemiImplicitEuler{}, nil
+	case VerletIntegrator:
+		return Verlet{}, nil
+	default:
+		return nil, fmt.Errorf("unknown integrator %s", name)
+	}
+}
+
+// Ticks returns the number of reference ticks in a time step of dt seconds.
+func Ticks(dt float64) float64 {
+	return dt * ReferenceTPS
+}
+
+// SemiImplicitEuler updates velocity from acceleration first,
+// then position from the new velocity.
+type SemiImplicitEuler struct{}
+
+// Integrate advances the body by dt seconds.
+func (SemiImplicitEuler) Integrate(pb *Body, dt float64) {
+	t := Ticks(dt)
+	pb.velocity.X += pb.acceleration.X * t
+	pb.velocity.Y += pb.acceleration.Y * t
+	pb.velocity.Limit(pb.maxVelocity)
+
+	pb.position.X += pb.velocity.X * t
+	pb.position.Y += pb.velocity.Y * t
+}
+
+// Verlet is a velocity Verlet integrator, which is exact for constant accelerations.
+type Verlet struct{}
+
+// Integrate advances the body by dt seconds.
+func (Verlet) Integrate(pb *Body, dt float64) {
+	t := Ticks(dt)
+	pb.position.X += pb.velocity.X*t + pb.acceleration.X*t*t/2
+	pb.position.Y += pb.velocity.Y*t + pb.acceleration.Y*t*t/2
+
+	pb.velocity.X += pb.acceleration.X * t
+	pb.velocity.Y += pb.acceleration.Y * t
+	pb.velocity.Limit(pb.maxVelocity)
+}
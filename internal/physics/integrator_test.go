@@ -0,0 +1,48 @@
+package physics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+func TestIntegrate(t *testing.T) {
+	tests := []struct {
+		name       string
+		integrator physics.Integrator
+		tps        int
+		want       float64
+	}{
+		// a constant acceleration of 0.1 during 60 reference ticks covers 0.1*60²/2 = 180 pixels
+		{name: "verlet 60 TPS", integrator: physics.Verlet{}, tps: 60, want: 180},
+		{name: "verlet 120 TPS", integrator: physics.Verlet{}, tps: 120, want: 180},
+		{name: "euler 60 TPS", integrator: physics.SemiImplicitEuler{}, tps: 60, want: 183},
+		{name: "euler 120 TPS", integrator: physics.SemiImplicitEuler{}, tps: 120, want: 181.5},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			b := physics.NewBody(0, 0, 10, 10)
+			b.Topology = topology.Infinite{}
+			b.Init(vector.Vector2D{})
+			b.LimitVelocity(1000)
+			b.SetIntegrator(tt.integrator)
+			b.Accelerate(vector.Vector2D{X: 0.1})
+			// integrate during one second
+			for i := 0; i < tt.tps; i++ {
+				b.Integrate(1 / float64(tt.tps))
+			}
+			if math.Abs(b.Position().X-tt.want) > 1e-6 {
+				t.Errorf("got position %f, want %f", b.Position().X, tt.want)
+			}
+			if math.Abs(b.Velocity().X-6) > 1e-6 {
+				t.Errorf("got velocity %f, want 6", b.Velocity().X)
+			}
+		})
+	}
+}
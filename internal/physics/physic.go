@@ -1,25 +1,26 @@
 package physics
 
 import (
+	"io"
 
-	// anonymous import for png decoder
-	_ "image/png"
-	"os"
-
-	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jtbonhomme/asteboids/internal/render"
 	"github.com/jtbonhomme/asteboids/internal/vector"
 )
 
 const (
 	defaultMaxVelocity float64 = 3.5
+	defaultMass        float64 = 1
 )
 
 const (
-	StarshipAgent string = "starship"
-	AsteroidAgent string = "asteroid"
-	RubbleAgent   string = "rubble"
-	BulletAgent   string = "bullet"
-	BoidAgent     string = "boid"
+	StarshipAgent     string = "starship"
+	AsteroidAgent     string = "asteroid"
+	RubbleAgent       string = "rubble"
+	BulletAgent       string = "bullet"
+	BoidAgent         string = "boid"
+	PredatorAgent     string = "predator"
+	SaucerAgent       string = "saucer"
+	SaucerBulletAgent string = "saucerBullet"
 )
 
 // Size represents coordonnates (X, Y) of a physical body.
@@ -36,9 +37,11 @@ type Size struct {
 
 type Physic interface {
 	// Draw draws the agent on screen.
-	Draw(*ebiten.Image)
-	// Update proceeds the agent state.
-	Update()
+	Draw(render.Screen)
+	// Update proceeds the agent state, dt seconds later.
+	Update(dt float64)
+	// SetIntegrator sets the integrator used to update the body.
+	SetIntegrator(Integrator)
 	// Init initializes the physic body.
 	Init(vector.Vector2D)
 	// ID displays physic body unique ID.
@@ -46,11 +49,16 @@ type Physic interface {
 	// String displays physic body information as a string.
 	String() string
 	// Intersect returns true if the physical body collide another one.
-	// Collision is computed based on Axis-Aligned Bounding Boxes.
-	// https://developer.mozilla.org/en-US/docs/Games/Techniques/2D_collision_detection
+	// Collision is computed based on the bodies collision shapes.
 	Intersect(Physic) bool
+	// Collide returns the contact between the physical body and another one, if they collide.
+	Collide(Physic) (Contact, bool)
+	// CollisionShape returns the physical body collision shape.
+	CollisionShape() Shape
+	// Angle returns physical body orientation (radian).
+	Angle() float64
 	// IntersectMultiple checks if multiple physical bodies are colliding with the first
-	IntersectMultiple(map[string]Physic) (string, bool)
+	IntersectMultiple([]Physic) (string, bool)
 	// position returns physical body position.
 	Position() vector.Vector2D
 	// Dimension returns physical body dimension.
@@ -61,8 +69,14 @@ type Physic interface {
 	Explode()
 	// Velocity returns physical body velocity.
 	Velocity() vector.Vector2D
+	// Mass returns physical body mass.
+	Mass() float64
+	// ApplyImpulse changes physical body velocity by impulse / mass.
+	ApplyImpulse(vector.Vector2D)
+	// Move set physical body position.
+	Move(vector.Vector2D)
 	// Dump write out internal agent's state.
-	Dump(*os.File) error
+	Dump(io.Writer) error
 }
 
 // AgentRegister is a function to register an agent.
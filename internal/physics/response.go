@@ -0,0 +1,36 @@
+package physics
+
+// Bounce resolves a collision between two bodies with an impulse along the contact normal.
+// restitution is 1 for an elastic collision, and 0 for a perfectly inelastic one.
+// Bodies are also moved apart, proportionally to their inverse mass, so that they do not overlap anymore.
+// The maximum velocity of bodies wins over the bounce: a body bounced faster than its limit is slowed down
+// by the next integration step, so the collision does not keep momentum anymore.
+func Bounce(a, b Physic, contact Contact, restitution float64) {
+	invA, invB := 1/a.Mass(), 1/b.Mass()
+	n := contact.Normal
+
+	// move bodies apart
+	correction := contact.Depth / (invA + invB)
+	posA, posB := a.Position(), b.Position()
+	posA.X -= n.X * correction * invA
+	posA.Y -= n.Y * correction * invA
+	posB.X += n.X * correction * invB
+	posB.Y += n.Y * correction * invB
+	a.Move(posA)
+	b.Move(posB)
+
+	// relative velocity along the normal, bodies already moving apart do not bounce
+	relative := b.Velocity()
+	relative.Subtract(a.Velocity())
+	vn := relative.X*n.X + relative.Y*n.Y
+	if vn >= 0 {
+		return
+	}
+
+	j := -(1 + restitution) * vn / (invA + invB)
+	impulse := n
+	impulse.Multiply(j)
+	b.ApplyImpulse(impulse)
+	impulse.Multiply(-1)
+	a.ApplyImpulse(impulse)
+}
@@ -0,0 +1,60 @@
+package physics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+func newMovingBody(x, vx, mass float64) *physics.Body {
+	b := physics.NewBody(x, 0, 20, 20)
+	b.Topology = topology.Infinite{}
+	b.Shape = physics.Circle{R: 10}
+	b.Init(vector.Vector2D{X: vx})
+	b.SetMass(mass)
+	return b
+}
+
+func TestBounce(t *testing.T) {
+	tests := []struct {
+		name        string
+		massA       float64
+		massB       float64
+		restitution float64
+		wantA       float64
+		wantB       float64
+	}{
+		{name: "elastic equal masses", massA: 1, massB: 1, restitution: 1, wantA: -1, wantB: 1},
+		{name: "inelastic equal masses", massA: 1, massB: 1, restitution: 0, wantA: 0, wantB: 0},
+		{name: "elastic heavy body", massA: 3, massB: 1, restitution: 1, wantA: 0, wantB: 2},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			a := newMovingBody(0, 1, tt.massA)
+			b := newMovingBody(15, -1, tt.massB)
+			momentum := a.Velocity().X*tt.massA + b.Velocity().X*tt.massB
+
+			contact, ok := a.Collide(b)
+			if !ok {
+				t.Fatal("expected bodies to collide")
+			}
+			physics.Bounce(a, b, contact, tt.restitution)
+
+			if math.Abs(a.Velocity().X-tt.wantA) > 1e-9 || math.Abs(b.Velocity().X-tt.wantB) > 1e-9 {
+				t.Errorf("got velocities %f and %f, want %f and %f", a.Velocity().X, b.Velocity().X, tt.wantA, tt.wantB)
+			}
+			if got := a.Velocity().X*tt.massA + b.Velocity().X*tt.massB; math.Abs(got-momentum) > 1e-9 {
+				t.Errorf("momentum is %f after bounce, want %f", got, momentum)
+			}
+			if a.Intersect(b) {
+				t.Errorf("expected bodies to be moved apart, got positions %v and %v", a.Position(), b.Position())
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package physics
+
+import (
+	"math"
+
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+// Shape is the collision shape of a physical body.
+// A shape is defined around the body position (its center),
+// and rotates with the body orientation.
+type Shape interface {
+	// BoundingRadius returns the radius of the smallest circle centered on the body position
+	// which encloses the shape.
+	BoundingRadius() float64
+}
+
+// Circle is a circular collision shape.
+type Circle struct {
+	R float64
+}
+
+// BoundingRadius returns the circle radius.
+func (c Circle) BoundingRadius() float64 {
+	return c.R
+}
+
+// Box is a rectangular collision shape, W being its length along the body orientation.
+type Box struct {
+	W float64
+	H float64
+}
+
+// BoundingRadius returns half of the box diagonal.
+func (b Box) BoundingRadius() float64 {
+	return math.Hypot(b.W, b.H) / 2
+}
+
+// Polygon is a convex polygon collision shape.
+// Points are relative to the body position, for an orientation of 0 (facing right).
+type Polygon struct {
+	Points []vector.Vector2D
+}
+
+// BoundingRadius returns the distance of the farthest point.
+func (p Polygon) BoundingRadius() float64 {
+	r := 0.0
+	for _, v := range p.Points {
+		r = math.Max(r, math.Sqrt(v.MagnitudeSquared()))
+	}
+	return r
+}
+
+// Contact describes the collision between two shapes.
+type Contact struct {
+	// Normal is the unit collision normal, pointing from the first shape to the second one.
+	Normal vector.Vector2D
+	// Depth is the penetration depth of the shapes along Normal.
+	Depth float64
+}
+
+// Vertices returns the world coordinates of a polygonal shape (box or polygon),
+// located at position with a given orientation.
+// It returns nil for a circle.
+func Vertices(s Shape, position vector.Vector2D, orientation float64) []vector.Vector2D {
+	var points []vector.Vector2D
+	switch shape := s.(type) {
+	case Box:
+		points = []vector.Vector2D{
+			{X: -shape.W / 2, Y: -shape.H / 2},
+			{X: shape.W / 2, Y: -shape.H / 2},
+			{X: shape.W / 2, Y: shape.H / 2},
+			{X: -shape.W / 2, Y: shape.H / 2},
+		}
+	case Polygon:
+		points = shape.Points
+	default:
+		return nil
+	}
+	sin, cos := math.Sincos(orientation)
+	vertices := make([]vector.Vector2D, len(points))
+	for i, p := range points {
+		vertices[i] = vector.Vector2D{
+			X: position.X + p.X*cos - p.Y*sin,
+			Y: position.Y + p.X*sin + p.Y*cos,
+		}
+	}
+	return vertices
+}
+
+// Collide runs the narrow phase collision test between shape a, located at posA with orientation thetaA,
+// and shape b, located at posB with orientation thetaB.
+// Polygons are tested with the Separating Axis Theorem.
+// https://en.wikipedia.org/wiki/Hyperplane_separation_theorem
+func Collide(a Shape, posA vector.Vector2D, thetaA float64, b Shape, posB vector.Vector2D, thetaB float64) (Contact, bool) {
+	// broad phase on the bounding circles
+	d := posB
+	d.Subtract(posA)
+	r := a.BoundingRadius() + b.BoundingRadius()
+	if d.MagnitudeSquared() >= r*r {
+		return Contact{}, false
+	}
+
+	circleA, aIsCircle := a.(Circle)
+	circleB, bIsCircle := b.(Circle)
+	switch {
+	case aIsCircle && bIsCircle:
+		return collideCircles(circleA.R, posA, circleB.R, posB)
+	case aIsCircle:
+		contact, ok := collideCirclePolygon(circleA.R, posA, Vertices(b, posB, thetaB))
+		return contact, ok
+	case bIsCircle:
+		contact, ok := collideCirclePolygon(circleB.R, posB, Vertices(a, posA, thetaA))
+		contact.Normal.Multiply(-1)
+		return contact, ok
+	default:
+		return collidePolygons(Vertices(a, posA, thetaA), Vertices(b, posB, thetaB))
+	}
+}
+
+func collideCircles(ra float64, posA vector.Vector2D, rb float64, posB vector.Vector2D) (Contact, bool) {
+	d := posB
+	d.Subtract(posA)
+	dist := math.Sqrt(d.MagnitudeSquared())
+	if dist >= ra+rb {
+		return Contact{}, false
+	}
+	normal := vector.Vector2D{X: 1, Y: 0}
+	if dist > 0 {
+		normal = d
+		normal.Divide(dist)
+	}
+	return Contact{Normal: normal, Depth: ra + rb - dist}, true
+}
+
+// collideCirclePolygon returns the contact from a circle to a polygon.
+func collideCirclePolygon(r float64, center vector.Vector2D, vertices []vector.Vector2D) (Contact, bool) {
+	if len(vertices) == 0 {
+		return Contact{}, false
+	}
+	axes := edgeNormals(vertices)
+	// the axis from the circle center to the closest vertex separates the circle from the polygon corners
+	closest := vertices[0]
+	for _, v := range vertices[1:] {
+		if distanceSquared(center, v) < distanceSquared(center, closest) {
+			closest = v
+		}
+	}
+	axis := closest
+	axis.Subtract(center)
+	if !axis.IsNil() {
+		axis.Normalize()
+		axes = append(axes, axis)
+	}
+
+	best := Contact{Depth: math.Inf(1)}
+	for _, axis := range axes {
+		c := dot(center, axis)
+		minA, maxA := c-r, c+r
+		minB, maxB := project(vertices, axis)
+		overlap := math.Min(maxA, maxB) - math.Max(minA, minB)
+		if overlap <= 0 {
+			return Contact{}, false
+		}
+		if overlap < best.Depth {
+			best = Contact{Normal: axis, Depth: overlap}
+		}
+	}
+	orient(&best, center, centroid(vertices))
+	return best, true
+}
+
+func collidePolygons(a, b []vector.Vector2D) (Contact, bool) {
+	if len(a) == 0 || len(b) == 0 {
+		return Contact{}, false
+	}
+	best := Contact{Depth: math.Inf(1)}
+	for _, axis := range append(edgeNormals(a), edgeNormals(b)...) {
+		minA, maxA := project(a, axis)
+		minB, maxB := project(b, axis)
+		overlap := math.Min(maxA, maxB) - math.Max(minA, minB)
+		if overlap <= 0 {
+			return Contact{}, false
+		}
+		if overlap < best.Depth {
+			best = Contact{Normal: axis, Depth: overlap}
+		}
+	}
+	orient(&best, centroid(a), centroid(b))
+	return best, true
+}
+
+// orient flips the contact normal so that it points from a to b.
+func orient(contact *Contact, a, b vector.Vector2D) {
+	d := b
+	d.Subtract(a)
+	if dot(d, contact.Normal) < 0 {
+		contact.Normal.Multiply(-1)
+	}
+}
+
+// edgeNormals returns the unit normal of each polygon edge.
+func edgeNormals(vertices []vector.Vector2D) []vector.Vector2D {
+	normals := make([]vector.Vector2D, 0, len(vertices))
+	for i := range vertices {
+		edge := vertices[(i+1)%len(vertices)]
+		edge.Subtract(vertices[i])
+		if edge.IsNil() {
+			continue
+		}
+		normal := vector.Vector2D{X: -edge.Y, Y: edge.X}
+		normal.Normalize()
+		normals = append(normals, normal)
+	}
+	return normals
+}
+
+// project returns the range covered by the vertices projected on an axis.
+func project(vertices []vector.Vector2D, axis vector.Vector2D) (float64, float64) {
+	min, max := math.Inf(1), math.Inf(-1)
+	for _, v := range vertices {
+		p := dot(v, axis)
+		min = math.Min(min, p)
+		max = math.Max(max, p)
+	}
+	return min, max
+}
+
+func centroid(vertices []vector.Vector2D) vector.Vector2D {
+	c := vector.Vector2D{}
+	for _, v := range vertices {
+		c.Add(v)
+	}
+	c.Divide(float64(len(vertices)))
+	return c
+}
+
+func dot(a, b vector.Vector2D) float64 {
+	return a.X*b.X + a.Y*b.Y
+}
+
+func distanceSquared(a, b vector.Vector2D) float64 {
+	d := b
+	d.Subtract(a)
+	return d.MagnitudeSquared()
+}
@@ -0,0 +1,99 @@
+package physics_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+func TestCollide(t *testing.T) {
+	type TestCase struct {
+		name      string
+		a         physics.Shape
+		posA      vector.Vector2D
+		thetaA    float64
+		b         physics.Shape
+		posB      vector.Vector2D
+		thetaB    float64
+		intersect bool
+		normal    vector.Vector2D
+		depth     float64
+	}
+
+	triangle := physics.Polygon{
+		Points: []vector.Vector2D{{X: 20, Y: 0}, {X: -20, Y: -20}, {X: -20, Y: 20}},
+	}
+	tests := []TestCase{
+		{
+			name: "circles", intersect: true,
+			a: physics.Circle{R: 10}, posA: vector.Vector2D{X: 0, Y: 0},
+			b: physics.Circle{R: 10}, posB: vector.Vector2D{X: 15, Y: 0},
+			normal: vector.Vector2D{X: 1, Y: 0}, depth: 5,
+		},
+		{
+			name: "distant circles", intersect: false,
+			a: physics.Circle{R: 10}, posA: vector.Vector2D{X: 0, Y: 0},
+			b: physics.Circle{R: 10}, posB: vector.Vector2D{X: 0, Y: 20},
+		},
+		{
+			name: "boxes", intersect: true,
+			a: physics.Box{W: 20, H: 20}, posA: vector.Vector2D{X: 0, Y: 0},
+			b: physics.Box{W: 20, H: 20}, posB: vector.Vector2D{X: 0, Y: -18},
+			normal: vector.Vector2D{X: 0, Y: -1}, depth: 2,
+		},
+		{
+			name: "rotated box corner", intersect: true,
+			a: physics.Box{W: 20, H: 20}, posA: vector.Vector2D{X: 0, Y: 0},
+			b: physics.Box{W: 20, H: 20}, posB: vector.Vector2D{X: 23, Y: 0}, thetaB: math.Pi / 4,
+			normal: vector.Vector2D{X: 1, Y: 0}, depth: 10 + 10*math.Sqrt2 - 23,
+		},
+		{
+			name: "unrotated box corner", intersect: false,
+			a: physics.Box{W: 20, H: 20}, posA: vector.Vector2D{X: 0, Y: 0},
+			b: physics.Box{W: 20, H: 20}, posB: vector.Vector2D{X: 23, Y: 0},
+		},
+		{
+			name: "circle near box corner", intersect: false,
+			a: physics.Circle{R: 5}, posA: vector.Vector2D{X: 14, Y: 14},
+			b: physics.Box{W: 20, H: 20}, posB: vector.Vector2D{X: 0, Y: 0},
+		},
+		{
+			name: "circle on box side", intersect: true,
+			a: physics.Box{W: 20, H: 20}, posA: vector.Vector2D{X: 0, Y: 0},
+			b: physics.Circle{R: 5}, posB: vector.Vector2D{X: 0, Y: 13},
+			normal: vector.Vector2D{X: 0, Y: 1}, depth: 2,
+		},
+		{
+			name: "circle behind triangle nose", intersect: false,
+			a: triangle, posA: vector.Vector2D{X: 0, Y: 0},
+			b: physics.Circle{R: 5}, posB: vector.Vector2D{X: 15, Y: 12},
+		},
+		{
+			name: "circle in front of rotated triangle nose", intersect: true,
+			a: triangle, posA: vector.Vector2D{X: 0, Y: 0}, thetaA: math.Pi / 2,
+			b: physics.Circle{R: 5}, posB: vector.Vector2D{X: 0, Y: 23},
+			normal: vector.Vector2D{X: 0, Y: 1}, depth: 2,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			contact, ok := physics.Collide(tt.a, tt.posA, tt.thetaA, tt.b, tt.posB, tt.thetaB)
+			if ok != tt.intersect {
+				t.Fatalf("test %s expected %t got %t", tt.name, tt.intersect, ok)
+			}
+			if !ok {
+				return
+			}
+			if math.Abs(contact.Normal.X-tt.normal.X) > 1e-9 || math.Abs(contact.Normal.Y-tt.normal.Y) > 1e-9 {
+				t.Errorf("test %s expected normal %v got %v", tt.name, tt.normal, contact.Normal)
+			}
+			if math.Abs(contact.Depth-tt.depth) > 1e-9 {
+				t.Errorf("test %s expected depth %0.3f got %0.3f", tt.name, tt.depth, contact.Depth)
+			}
+		})
+	}
+}
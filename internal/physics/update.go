@@ -2,6 +2,10 @@ package physics
 
 import (
 	"math"
+
+	"github.com/jtbonhomme/asteboids/internal/vector"
+
+	"github.com/jtbonhomme/asteboids/internal/topology"
 )
 
 func (pb *Body) normalizeOrientation() {
@@ -19,20 +23,34 @@ func (pb *Body) Rotate(rotationAngle float64) {
 	pb.normalizeOrientation()
 }
 
-// Update is called every tick (1/60 [s] by default).
+// Update is called every physics step, dt seconds long (1/60 [s] by default).
 // Update proceeds the agent state.
-func (pb *Body) Update() {
-	pb.UpdateVelocity()
-	pb.UpdatePosition()
+func (pb *Body) Update(dt float64) {
+	pb.Integrate(dt)
+}
+
+// Integrate computes new velocity, position and orientation after dt seconds.
+func (pb *Body) Integrate(dt float64) {
+	integrator := pb.Integrator
+	if integrator == nil {
+		integrator = SemiImplicitEuler{}
+	}
+	integrator.Integrate(pb, dt)
+	pb.position, pb.velocity = pb.World().Constrain(pb.position, pb.velocity)
+	if pb.angularVelocity != 0 {
+		pb.Rotate(pb.angularVelocity * Ticks(dt))
+	}
 }
 
-// UpdateVelocity computes new velocity.
-func (pb *Body) UpdateVelocity() {
-	// update velocity from acceleration
-	pb.velocity.Add(pb.acceleration)
+// ApplyImpulse changes physical body velocity by impulse / mass.
+func (pb *Body) ApplyImpulse(impulse vector.Vector2D) {
+	impulse.Divide(pb.mass)
+	pb.velocity.Add(impulse)
+}
 
-	// limit velocity to max value
-	pb.velocity.Limit(pb.maxVelocity)
+// SetIntegrator sets the integrator used to update the body.
+func (pb *Body) SetIntegrator(integrator Integrator) {
+	pb.Integrator = integrator
 }
 
 // UpdateOrientation computes orientation from velocity.
@@ -44,18 +62,14 @@ func (pb *Body) UpdateOrientation() {
 	pb.normalizeOrientation()
 }
 
-// UpdatePosition compute new position.
-func (pb *Body) UpdatePosition() {
-	pb.position.Add(pb.velocity)
-
-	if pb.position.X > pb.ScreenWidth {
-		pb.position.X = 0
-	} else if pb.position.X < 0 {
-		pb.position.X = pb.ScreenWidth
+// World returns the topology of the world the body lives in.
+// Without topology, the body lives in a toroidal world of the screen size.
+func (pb *Body) World() topology.Topology {
+	if pb.Topology != nil {
+		return pb.Topology
 	}
-	if pb.position.Y > pb.ScreenHeight {
-		pb.position.Y = 0
-	} else if pb.position.Y < 0 {
-		pb.position.Y = pb.ScreenHeight
+	return topology.Toroidal{
+		Width:  pb.ScreenWidth,
+		Height: pb.ScreenHeight,
 	}
 }
@@ -3,20 +3,16 @@ package physics
 import (
 	"fmt"
 	"image/color"
+	"io"
 	"math"
-	"os"
 
-	// anonymous import for png decoder
-	_ "image/png"
-
-	"github.com/hajimehoshi/ebiten/v2"
-	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/jtbonhomme/asteboids/internal/render"
 	"github.com/jtbonhomme/asteboids/internal/vector"
 )
 
 // ID displays physic body unique ID.
 func (pb *Body) ID() string {
-	return pb.id.String()
+	return pb.id
 }
 
 // String displays physic body information as a string.
@@ -36,20 +32,41 @@ func (pb *Body) String() string {
 }
 
 // Intersect returns true if the physical body collide another one.
-// Collision is computed based on Axis-Aligned Bounding Boxes.
-// https://developer.mozilla.org/en-US/docs/Games/Techniques/2D_collision_detection
+// Collision is computed based on the bodies collision shapes.
 func (pb *Body) Intersect(p Physic) bool {
-	ax, ay := pb.position.X, pb.position.Y
-	aw, ah := pb.Dimension().W, pb.Dimension().H
+	_, ok := pb.Collide(p)
+	return ok
+}
 
-	bx, by := p.Position().X, p.Position().Y
-	bw, bh := p.Dimension().W, p.Dimension().H
+// Collide returns the contact between the physical body and another one, if they collide.
+// The contact normal points from the body to the other one.
+func (pb *Body) Collide(p Physic) (Contact, bool) {
+	// use the closest image of p, which may be across the world edges
+	position := pb.position
+	position.Add(pb.World().Delta(pb.position, p.Position()))
+	return Collide(pb.CollisionShape(), pb.position, pb.Orientation,
+		p.CollisionShape(), position, p.Angle())
+}
+
+// CollisionShape returns the physical body collision shape.
+// Without shape, the body collides as a box of its dimension.
+func (pb *Body) CollisionShape() Shape {
+	if pb.Shape != nil {
+		return pb.Shape
+	}
+	return Box{
+		W: pb.PhysicWidth,
+		H: pb.PhysicHeight,
+	}
+}
 
-	return (ax < bx+bw && ay < by+bh) && (ax+aw > bx && ay+ah > by)
+// Angle returns physical body orientation (radian).
+func (pb *Body) Angle() float64 {
+	return pb.Orientation
 }
 
 // IntersectMultiple checks if multiple physical bodies are colliding with the first
-func (pb *Body) IntersectMultiple(physics map[string]Physic) (string, bool) {
+func (pb *Body) IntersectMultiple(physics []Physic) (string, bool) {
 	for _, p := range physics {
 		if pb.Intersect(p) {
 			pb.Log.Warnf("%s [%d , %d] (%dx%d) intersect with %s [%d , %d] (%dx%d)",
@@ -83,6 +100,26 @@ func (pb *Body) Velocity() vector.Vector2D {
 	return pb.velocity
 }
 
+// Mass returns physical body mass.
+func (pb *Body) Mass() float64 {
+	return pb.mass
+}
+
+// SetMass sets physical body mass.
+func (pb *Body) SetMass(mass float64) {
+	pb.mass = mass
+}
+
+// AngularVelocity returns physical body angular velocity (radian per 1/60 [s]).
+func (pb *Body) AngularVelocity() float64 {
+	return pb.angularVelocity
+}
+
+// Spin sets physical body angular velocity (radian per 1/60 [s]).
+func (pb *Body) Spin(angularVelocity float64) {
+	pb.angularVelocity = angularVelocity
+}
+
 // LimitVelocity limits the physical body maximum velocity.
 func (pb *Body) LimitVelocity(maxVelocity float64) {
 	pb.maxVelocity = maxVelocity
@@ -103,44 +140,23 @@ func (pb *Body) Acceleration() vector.Vector2D {
 	return pb.acceleration
 }
 
-// DrawBodyBoundaryBox draws a box around the body, based on its dimension.
-func (pb *Body) DrawBodyBoundaryBox(screen *ebiten.Image) {
-	// Top boundary
-	ebitenutil.DrawLine(
-		screen,
-		pb.position.X-pb.PhysicWidth/2,
-		pb.position.Y-pb.PhysicHeight/2,
-		pb.position.X+pb.PhysicWidth/2,
-		pb.position.Y-pb.PhysicHeight/2,
-		color.Gray16{0x6666},
-	)
-	// Right boundary
-	ebitenutil.DrawLine(
-		screen,
-		pb.position.X+pb.PhysicWidth/2,
-		pb.position.Y-pb.PhysicHeight/2,
-		pb.position.X+pb.PhysicWidth/2,
-		pb.position.Y+pb.PhysicHeight/2,
-		color.Gray16{0x6666},
-	)
-	// Bottom boundary
-	ebitenutil.DrawLine(
-		screen,
-		pb.position.X-pb.PhysicWidth/2,
-		pb.position.Y+pb.PhysicHeight/2,
-		pb.position.X+pb.PhysicWidth/2,
-		pb.position.Y+pb.PhysicHeight/2,
-		color.Gray16{0x6666},
-	)
-	// Left boundary
-	ebitenutil.DrawLine(
-		screen,
-		pb.position.X-pb.PhysicWidth/2,
-		pb.position.Y-pb.PhysicHeight/2,
-		pb.position.X-pb.PhysicWidth/2,
-		pb.position.Y+pb.PhysicHeight/2,
-		color.Gray16{0x6666},
-	)
+// DrawShape draws the outline of the body collision shape.
+func (pb *Body) DrawShape(screen render.Screen) {
+	const circleSegments int = 16
+	vertices := Vertices(pb.CollisionShape(), pb.position, pb.Orientation)
+	if c, ok := pb.CollisionShape().(Circle); ok {
+		for i := 0; i < circleSegments; i++ {
+			theta := 2 * math.Pi * float64(i) / float64(circleSegments)
+			vertices = append(vertices, vector.Vector2D{
+				X: pb.position.X + c.R*math.Cos(theta),
+				Y: pb.position.Y + c.R*math.Sin(theta),
+			})
+		}
+	}
+	for i := range vertices {
+		next := vertices[(i+1)%len(vertices)]
+		screen.DrawLine(vertices[i].X, vertices[i].Y, next.X, next.Y, color.Gray16{0x6666})
+	}
 }
 
 func isElementOf(elt string, arr []string) bool {
@@ -153,14 +169,14 @@ func isElementOf(elt string, arr []string) bool {
 }
 
 // LinkAgents draws a perimter around the body, based on a given radius.
-func (pb *Body) LinkAgents(screen *ebiten.Image, agents []Physic, agentTypes []string) {
+func (pb *Body) LinkAgents(screen render.Screen, agents []Physic, agentTypes []string) {
 	for _, a := range agents {
 		if isElementOf(a.Type(), agentTypes) {
-			// Draw line between agents
-			ebitenutil.DrawLine(
-				screen,
+			// Draw line between agents, which may be across the world edges
+			delta := pb.World().Delta(pb.Position(), a.Position())
+			screen.DrawLine(
 				pb.Position().X, pb.Position().Y,
-				a.Position().X, a.Position().Y,
+				pb.Position().X+delta.X, pb.Position().Y+delta.Y,
 				color.Gray16{0x2264},
 			)
 		}
@@ -178,8 +194,8 @@ func (pb *Body) Explode() {
 }
 
 // Dump write out internal agent's state.
-func (pb *Body) Dump(f *os.File) error {
-	_, err := f.Write([]byte("\n *** " + pb.ID() + " ***\n" + pb.String() + "\n"))
+func (pb *Body) Dump(w io.Writer) error {
+	_, err := w.Write([]byte("\n *** " + pb.ID() + " ***\n" + pb.String() + "\n"))
 	return err
 }
 
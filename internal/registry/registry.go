@@ -0,0 +1,198 @@
+// Package registry keeps track of the agents living in a game.
+package registry
+
+import (
+	"github.com/jtbonhomme/asteboids/internal/physics"
+)
+
+// Hook is called when an agent spawns into or is destroyed from the registry.
+type Hook func(physics.Physic)
+
+// Registry holds the game agents, in spawn order, with a view per agent type.
+// Agents added or removed while the registry is ranged over are only
+// spawned or destroyed once the iteration ends, so that iterations never
+// see the registry change.
+type Registry struct {
+	agents    []physics.Physic
+	byType    map[string][]physics.Physic
+	byID      map[string]physics.Physic
+	toAdd     []physics.Physic
+	toRemove  []string
+	removed   map[string]bool
+	iterating int
+	onSpawn   []Hook
+	onDestroy []Hook
+}
+
+// New creates an empty registry.
+func New() *Registry {
+	return &Registry{
+		byType:  make(map[string][]physics.Physic),
+		byID:    make(map[string]physics.Physic),
+		removed: make(map[string]bool),
+	}
+}
+
+// OnSpawn registers a hook called for every agent added to the registry.
+func (r *Registry) OnSpawn(hook Hook) {
+	r.onSpawn = append(r.onSpawn, hook)
+}
+
+// OnDestroy registers a hook called for every agent removed from the registry.
+func (r *Registry) OnDestroy(hook Hook) {
+	r.onDestroy = append(r.onDestroy, hook)
+}
+
+// Add adds an agent to the registry.
+// During an iteration, the agent spawns when the iteration ends.
+func (r *Registry) Add(agent physics.Physic) {
+	if _, ok := r.byID[agent.ID()]; ok {
+		return
+	}
+	r.byID[agent.ID()] = agent
+	r.toAdd = append(r.toAdd, agent)
+	r.flushIfIdle()
+}
+
+// Remove removes an agent from the registry.
+// During an iteration, the agent is destroyed when the iteration ends,
+// but it is not considered alive anymore.
+func (r *Registry) Remove(id string) {
+	if _, ok := r.byID[id]; !ok || r.removed[id] {
+		return
+	}
+	r.removed[id] = true
+	r.toRemove = append(r.toRemove, id)
+	r.flushIfIdle()
+}
+
+// Contains returns true if an agent is alive in the registry.
+func (r *Registry) Contains(id string) bool {
+	_, ok := r.byID[id]
+	return ok && !r.removed[id]
+}
+
+// Get returns an alive agent by ID.
+func (r *Registry) Get(id string) (physics.Physic, bool) {
+	if !r.Contains(id) {
+		return nil, false
+	}
+	return r.byID[id], true
+}
+
+// Len returns the number of spawned agents.
+func (r *Registry) Len() int {
+	return len(r.agents)
+}
+
+// Count returns the number of spawned agents of the given types.
+func (r *Registry) Count(agentTypes ...string) int {
+	n := 0
+	for _, agentType := range agentTypes {
+		n += len(r.byType[agentType])
+	}
+	return n
+}
+
+// All returns all spawned agents, in spawn order.
+// The returned slice must not be modified.
+func (r *Registry) All() []physics.Physic {
+	return r.agents
+}
+
+// OfType returns the spawned agents of the given types, type after type, in spawn order.
+// The returned slice must not be modified.
+func (r *Registry) OfType(agentTypes ...string) []physics.Physic {
+	if len(agentTypes) == 1 {
+		return r.byType[agentTypes[0]]
+	}
+	agents := make([]physics.Physic, 0, r.Count(agentTypes...))
+	for _, agentType := range agentTypes {
+		agents = append(agents, r.byType[agentType]...)
+	}
+	return agents
+}
+
+// Range calls fn for every alive agent of the given types, or of all types if none is given.
+// Agents added or removed by fn spawn or are destroyed after the iteration.
+func (r *Registry) Range(fn func(physics.Physic), agentTypes ...string) {
+	agents := r.agents
+	if len(agentTypes) > 0 {
+		agents = r.OfType(agentTypes...)
+	}
+	r.iterating++
+	for _, a := range agents {
+		if !r.removed[a.ID()] {
+			fn(a)
+		}
+	}
+	r.iterating--
+	r.flushIfIdle()
+}
+
+// Clear removes all agents, without calling hooks.
+func (r *Registry) Clear() {
+	r.agents = nil
+	r.byType = make(map[string][]physics.Physic)
+	r.byID = make(map[string]physics.Physic)
+	r.toAdd = nil
+	r.toRemove = nil
+	r.removed = make(map[string]bool)
+}
+
+func (r *Registry) flushIfIdle() {
+	if r.iterating == 0 {
+		r.flush()
+	}
+}
+
+// flush spawns added agents, then destroys removed ones, so that an agent added
+// and removed during a same iteration still goes through both hooks.
+// Views are rebuilt rather than modified in place, so that slices
+// previously returned by All and OfType are left untouched.
+func (r *Registry) flush() {
+	// hooks may add or remove agents, which are handled by the next loop
+	r.iterating++
+	defer func() { r.iterating-- }()
+	for len(r.toRemove) > 0 || len(r.toAdd) > 0 {
+		toAdd := r.toAdd
+		r.toAdd = nil
+		for _, agent := range toAdd {
+			r.agents = append(r.agents[:len(r.agents):len(r.agents)], agent)
+			agents := r.byType[agent.Type()]
+			r.byType[agent.Type()] = append(agents[:len(agents):len(agents)], agent)
+			for _, hook := range r.onSpawn {
+				hook(agent)
+			}
+		}
+
+		toRemove := r.toRemove
+		r.toRemove = nil
+		if len(toRemove) == 0 {
+			continue
+		}
+		r.agents = r.without(r.agents)
+		for agentType, agents := range r.byType {
+			r.byType[agentType] = r.without(agents)
+		}
+		for _, id := range toRemove {
+			agent := r.byID[id]
+			delete(r.byID, id)
+			delete(r.removed, id)
+			for _, hook := range r.onDestroy {
+				hook(agent)
+			}
+		}
+	}
+}
+
+// without returns the agents which are not removed.
+func (r *Registry) without(agents []physics.Physic) []physics.Physic {
+	result := make([]physics.Physic, 0, len(agents))
+	for _, a := range agents {
+		if !r.removed[a.ID()] {
+			result = append(result, a)
+		}
+	}
+	return result
+}
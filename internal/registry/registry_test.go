@@ -0,0 +1,106 @@
+package registry_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/registry"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+func newAgent(rng *rand.Rand, agentType string) *physics.Body {
+	b := physics.NewBody(0, 0, 10, 10)
+	b.Rand = rng
+	b.AgentType = agentType
+	b.Init(vector.Vector2D{})
+	return b
+}
+
+func ids(agents []physics.Physic) []string {
+	result := []string{}
+	for _, a := range agents {
+		result = append(result, a.ID())
+	}
+	return result
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestViews(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	r := registry.New()
+	boid1 := newAgent(rng, physics.BoidAgent)
+	asteroid := newAgent(rng, physics.AsteroidAgent)
+	boid2 := newAgent(rng, physics.BoidAgent)
+	for _, a := range []physics.Physic{boid1, asteroid, boid2} {
+		r.Add(a)
+	}
+
+	if got, want := ids(r.All()), []string{boid1.ID(), asteroid.ID(), boid2.ID()}; !equal(got, want) {
+		t.Errorf("All() = %v, want spawn order %v", got, want)
+	}
+	if got, want := ids(r.OfType(physics.AsteroidAgent, physics.BoidAgent)), []string{asteroid.ID(), boid1.ID(), boid2.ID()}; !equal(got, want) {
+		t.Errorf("OfType() = %v, want %v", got, want)
+	}
+	if r.Count(physics.BoidAgent) != 2 {
+		t.Errorf("got %d boids, want 2", r.Count(physics.BoidAgent))
+	}
+
+	r.Remove(boid1.ID())
+	if r.Contains(boid1.ID()) || r.Len() != 2 || r.Count(physics.BoidAgent) != 1 {
+		t.Errorf("expected boid %s to be removed", boid1.ID())
+	}
+}
+
+func TestDeferredChanges(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	r := registry.New()
+	spawned, destroyed := []string{}, []string{}
+	r.OnSpawn(func(a physics.Physic) { spawned = append(spawned, a.ID()) })
+	r.OnDestroy(func(a physics.Physic) { destroyed = append(destroyed, a.ID()) })
+
+	first := newAgent(rng, physics.BoidAgent)
+	second := newAgent(rng, physics.BoidAgent)
+	r.Add(first)
+	r.Add(second)
+	child := newAgent(rng, physics.BoidAgent)
+
+	visited := []string{}
+	r.Range(func(a physics.Physic) {
+		visited = append(visited, a.ID())
+		if a.ID() == first.ID() {
+			r.Remove(second.ID())
+			r.Add(child)
+			if r.Len() != 2 {
+				t.Errorf("registry changed during iteration, got %d agents", r.Len())
+			}
+			if r.Contains(second.ID()) {
+				t.Errorf("removed agent %s is still alive", second.ID())
+			}
+		}
+	})
+
+	if want := []string{first.ID()}; !equal(visited, want) {
+		t.Errorf("visited %v, want %v", visited, want)
+	}
+	if want := []string{first.ID(), child.ID()}; !equal(ids(r.All()), want) {
+		t.Errorf("All() = %v after iteration, want %v", ids(r.All()), want)
+	}
+	if want := []string{first.ID(), second.ID(), child.ID()}; !equal(spawned, want) {
+		t.Errorf("spawned %v, want %v", spawned, want)
+	}
+	if want := []string{second.ID()}; !equal(destroyed, want) {
+		t.Errorf("destroyed %v, want %v", destroyed, want)
+	}
+}
@@ -0,0 +1,68 @@
+package render
+
+import "math"
+
+// GeoM is a 2D affine transformation matrix.
+// The zero value is the identity matrix.
+//
+//	| a+1  b   tx |
+//	|  c  d+1  ty |
+type GeoM struct {
+	a, b, c, d float64
+	tx, ty     float64
+}
+
+// Element returns the value of the matrix at (i, j).
+func (g *GeoM) Element(i, j int) float64 {
+	switch {
+	case i == 0 && j == 0:
+		return g.a + 1
+	case i == 0 && j == 1:
+		return g.b
+	case i == 0 && j == 2:
+		return g.tx
+	case i == 1 && j == 0:
+		return g.c
+	case i == 1 && j == 1:
+		return g.d + 1
+	case i == 1 && j == 2:
+		return g.ty
+	default:
+		return 0
+	}
+}
+
+// Translate translates the matrix by (tx, ty).
+func (g *GeoM) Translate(tx, ty float64) {
+	g.tx += tx
+	g.ty += ty
+}
+
+// Scale scales the matrix by (x, y).
+func (g *GeoM) Scale(x, y float64) {
+	a, b, c, d := g.a+1, g.b, g.c, g.d+1
+	g.a = a*x - 1
+	g.b = b * x
+	g.c = c * y
+	g.d = d*y - 1
+	g.tx *= x
+	g.ty *= y
+}
+
+// Rotate rotates the matrix by theta (radian).
+func (g *GeoM) Rotate(theta float64) {
+	sin, cos := math.Sincos(theta)
+	a, b, c, d := g.a+1, g.b, g.c, g.d+1
+	tx, ty := g.tx, g.ty
+	g.a = cos*a - sin*c - 1
+	g.b = cos*b - sin*d
+	g.c = sin*a + cos*c
+	g.d = sin*b + cos*d - 1
+	g.tx = cos*tx - sin*ty
+	g.ty = sin*tx + cos*ty
+}
+
+// Apply applies the matrix to the point (x, y).
+func (g *GeoM) Apply(x, y float64) (float64, float64) {
+	return (g.a+1)*x + g.b*y + g.tx, g.c*x + (g.d+1)*y + g.ty
+}
@@ -0,0 +1,68 @@
+package render
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+)
+
+// Headless is a Renderer which does not need any display.
+// It is used to run the game without window, e.g. in simulations.
+type Headless struct {
+	TPS float64
+}
+
+type headlessImage struct {
+	width, height int
+}
+
+// Size returns the image width and height (in pixels).
+func (i headlessImage) Size() (int, int) {
+	return i.width, i.height
+}
+
+// NewImage creates an image which only keeps the picture dimension.
+func (h *Headless) NewImage(img image.Image) Image {
+	if img == nil {
+		return headlessImage{}
+	}
+	b := img.Bounds()
+	return headlessImage{
+		width:  b.Dx(),
+		height: b.Dy(),
+	}
+}
+
+// CurrentTPS returns the simulated number of ticks per second.
+func (h *Headless) CurrentTPS() float64 {
+	return h.TPS
+}
+
+// CurrentFPS always returns 0, nothing is drawn in headless mode.
+func (h *Headless) CurrentFPS() float64 {
+	return 0
+}
+
+// NullScreen is a Screen which discards everything drawn on it.
+type NullScreen struct{}
+
+// Fill does nothing.
+func (NullScreen) Fill(color.Color) {}
+
+// DrawImage does nothing.
+func (NullScreen) DrawImage(Image, GeoM) {}
+
+// DrawLine does nothing.
+func (NullScreen) DrawLine(x1, y1, x2, y2 float64, clr color.Color) {}
+
+// DrawText does nothing.
+func (NullScreen) DrawText(string, font.Face, int, int, color.Color) {}
+
+// BoundString returns an empty rectangle.
+func (NullScreen) BoundString(font.Face, string) image.Rectangle {
+	return image.Rectangle{}
+}
+
+// DebugPrint does nothing.
+func (NullScreen) DebugPrint(string) {}
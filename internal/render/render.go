@@ -0,0 +1,41 @@
+package render
+
+import (
+	"image"
+	"image/color"
+
+	"golang.org/x/image/font"
+)
+
+// Image is a drawable picture owned by a Renderer.
+type Image interface {
+	// Size returns the image width and height (in pixels).
+	Size() (int, int)
+}
+
+// Screen is the surface agents draw themselves on.
+// Screen is provided to Draw every frame (typically 1/60[s] for 60Hz display).
+type Screen interface {
+	// Fill fills the whole screen with a color.
+	Fill(clr color.Color)
+	// DrawImage draws an image on the screen, transformed by a geometry matrix.
+	DrawImage(img Image, geoM GeoM)
+	// DrawLine draws a line segment between (x1, y1) and (x2, y2).
+	DrawLine(x1, y1, x2, y2 float64, clr color.Color)
+	// DrawText draws a text, (x, y) being the position of the text baseline.
+	DrawText(msg string, face font.Face, x, y int, clr color.Color)
+	// BoundString returns the bounding box of a text, as it would be drawn by DrawText.
+	BoundString(face font.Face, msg string) image.Rectangle
+	// DebugPrint draws a debug message in the top left corner.
+	DebugPrint(msg string)
+}
+
+// Renderer creates images and reports rendering statistics.
+type Renderer interface {
+	// NewImage creates a drawable image from a decoded picture.
+	NewImage(img image.Image) Image
+	// CurrentTPS returns the current number of ticks per second.
+	CurrentTPS() float64
+	// CurrentFPS returns the current number of frames per second.
+	CurrentFPS() float64
+}
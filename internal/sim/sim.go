@@ -0,0 +1,63 @@
+package sim
+
+import (
+	"errors"
+	"time"
+
+	"github.com/jtbonhomme/asteboids/internal/config"
+	"github.com/jtbonhomme/asteboids/internal/game"
+	"github.com/jtbonhomme/asteboids/internal/input"
+	"github.com/jtbonhomme/asteboids/internal/metrics"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"github.com/sirupsen/logrus"
+)
+
+// Run runs the game without window for conf.Ticks ticks, or until the game is over.
+// Ticks are run as fast as possible, unless conf.Realtime is set.
+func Run(log *logrus.Logger, conf *config.Config) error {
+	if conf.MaxTPS <= 0 {
+		return errors.New("maxTPS must be strictly positive in sim mode")
+	}
+	renderer := &render.Headless{
+		TPS: float64(conf.MaxTPS),
+	}
+	g := game.New(log, conf, renderer, input.None{})
+	if conf.Metrics.File != "" {
+		exporter, err := metrics.Create(conf.Metrics.File, conf.Metrics.Format)
+		if err != nil {
+			return err
+		}
+		defer exporter.Close()
+		g.ExportMetrics(exporter)
+	}
+	g.StartGame()
+
+	var ticker *time.Ticker
+	if conf.Realtime {
+		ticker = time.NewTicker(time.Second / time.Duration(conf.MaxTPS))
+		defer ticker.Stop()
+	}
+
+	start := time.Now()
+	ticks := 0
+	for ticks < conf.Ticks && !g.IsOver() {
+		ticks++
+		if ticker != nil {
+			<-ticker.C
+		}
+		err := g.Update()
+		if err != nil {
+			return err
+		}
+		if ticks%conf.MaxTPS == 0 {
+			log.Debugf("tick %d: %d agents, score %d", ticks, g.AgentsCount(), g.Score())
+		}
+	}
+	log.Infof("Simulated %d ticks in %s: %d agents, score %d, game over %t",
+		ticks,
+		time.Since(start).Round(time.Millisecond),
+		g.AgentsCount(),
+		g.Score(),
+		g.IsOver())
+	return nil
+}
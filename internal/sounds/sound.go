@@ -1,55 +1,132 @@
 package sounds
 
 import (
-	// import embed to load truetype font
+	// import embed to load sound files
 	_ "embed"
-
-	"github.com/hajimehoshi/ebiten/v2/audio"
 )
 
+// Sound identifies one of the game sound effects.
+type Sound int
+
 const (
-	sampleRate = 11025
+	Fire Sound = iota
+	Thrust
+	Beat1
+	Beat2
+	BangSmall
+	BangMedium
+	BangLarge
+	ExtraShip
+	SaucerBig
+	SaucerSmall
 )
 
-var audioContext *audio.Context
-
 //go:embed fire.wav
 var fireWAV []byte
-var FirePlayer *audio.Player
 
 //go:embed thrust.wav
 var thrustWAV []byte
-var ThrustPlayer *audio.Player
 
 //go:embed beat1.wav
 var beat1WAV []byte
-var Beat1Player *audio.Player
 
 //go:embed beat2.wav
 var beat2WAV []byte
-var Beat2Player *audio.Player
 
 //go:embed bangSmall.wav
 var bangSmallWAV []byte
-var BangSmallPlayer *audio.Player
 
 //go:embed bangMedium.wav
 var bangMediumWAV []byte
-var BangMediumPlayer *audio.Player
 
 //go:embed bangLarge.wav
 var bangLargeWAV []byte
-var BangLargePlayer *audio.Player
-
-func Init() {
-	audioContext = audio.NewContext(sampleRate)
-	FirePlayer = audio.NewPlayerFromBytes(audioContext, fireWAV)
-	ThrustPlayer = audio.NewPlayerFromBytes(audioContext, thrustWAV)
-	Beat1Player = audio.NewPlayerFromBytes(audioContext, beat1WAV)
-	Beat2Player = audio.NewPlayerFromBytes(audioContext, beat2WAV)
-	BangSmallPlayer = audio.NewPlayerFromBytes(audioContext, bangSmallWAV)
-	BangMediumPlayer = audio.NewPlayerFromBytes(audioContext, bangMediumWAV)
-	BangLargePlayer = audio.NewPlayerFromBytes(audioContext, bangLargeWAV)
+
+//go:embed extraShip.wav
+var extraShipWAV []byte
+
+//go:embed saucerBig.wav
+var saucerBigWAV []byte
+
+//go:embed saucerSmall.wav
+var saucerSmallWAV []byte
+
+// Sounds lists all the game sound effects.
+var Sounds = []Sound{Fire, Thrust, Beat1, Beat2, BangSmall, BangMedium, BangLarge, ExtraShip}
+
+// Loops lists the game sound effects played in a loop.
+var Loops = []Sound{SaucerBig, SaucerSmall}
+
+// Player plays sounds on an audio device.
+type Player interface {
+	// Play rewinds and plays a sound.
+	Play(Sound)
+	// Loop plays a sound in a loop, until it is stopped.
+	Loop(Sound)
+	// Stop stops a sound played in a loop.
+	Stop(Sound)
+	// SetVolume sets the volume of all sounds.
+	SetVolume(float64)
+}
+
+var player Player
+
+// WAV returns the raw WAV data of a sound.
+func WAV(s Sound) []byte {
+	switch s {
+	case Fire:
+		return fireWAV
+	case Thrust:
+		return thrustWAV
+	case Beat1:
+		return beat1WAV
+	case Beat2:
+		return beat2WAV
+	case BangSmall:
+		return bangSmallWAV
+	case BangMedium:
+		return bangMediumWAV
+	case BangLarge:
+		return bangLargeWAV
+	case ExtraShip:
+		return extraShipWAV
+	case SaucerBig:
+		return saucerBigWAV
+	case SaucerSmall:
+		return saucerSmallWAV
+	default:
+		return nil
+	}
+}
+
+// Init sets the audio player used to play sounds.
+// Until Init is called, sounds are silently discarded.
+func Init(p Player) {
+	player = p
+}
+
+// Play plays a sound, if an audio player is set.
+func Play(s Sound) {
+	if player == nil {
+		return
+	}
+	player.Play(s)
+}
+
+// Loop plays a sound in a loop, if an audio player is set.
+func Loop(s Sound) {
+	if player == nil {
+		return
+	}
+	player.Loop(s)
+}
+
+// Stop stops a sound played in a loop, if an audio player is set.
+func Stop(s Sound) {
+	if player == nil {
+		return
+	}
+	player.Stop(s)
 }
 
 func Mute() {
@@ -61,14 +138,8 @@ func Unmute() {
 }
 
 func SetVolume(v float64) {
-	if v < 0 || v > 1 {
+	if v < 0 || v > 1 || player == nil {
 		return
 	}
-	FirePlayer.SetVolume(v)
-	ThrustPlayer.SetVolume(v)
-	Beat1Player.SetVolume(v)
-	Beat2Player.SetVolume(v)
-	BangSmallPlayer.SetVolume(v)
-	BangMediumPlayer.SetVolume(v)
-	BangLargePlayer.SetVolume(v)
+	player.SetVolume(v)
 }
@@ -0,0 +1,220 @@
+package spatial
+
+import (
+	"math"
+
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+// Grid is a spatial index which splits the screen into square cells.
+// Agents are bucketed in the cell containing their position, so that a radius query
+// only checks the agents of the cells overlapping the query circle.
+// Agents outside of the screen are bucketed in the closest edge cell.
+// Grid is meant to be rebuilt every tick.
+type Grid struct {
+	topology  topology.Topology
+	width     float64
+	height    float64
+	cellSize  float64
+	cols      int
+	rows      int
+	cells     [][]physics.Physic
+	cellOf    map[string]int
+	maxExtent float64
+}
+
+// NewGrid creates a grid covering a width x height screen, with cellSize x cellSize cells.
+// Distances are measured in topo, so that queries cross the edges of a toroidal world.
+// Queries are cheaper when cellSize is close to the usual query radius.
+func NewGrid(topo topology.Topology, width, height, cellSize float64) *Grid {
+	if cellSize <= 0 {
+		cellSize = math.Max(width, height)
+	}
+	cols := int(math.Ceil(width / cellSize))
+	rows := int(math.Ceil(height / cellSize))
+	if cols < 1 {
+		cols = 1
+	}
+	if rows < 1 {
+		rows = 1
+	}
+	return &Grid{
+		topology: topo,
+		width:    width,
+		height:   height,
+		cellSize: cellSize,
+		cols:     cols,
+		rows:     rows,
+		cells:    make([][]physics.Physic, cols*rows),
+		cellOf:   make(map[string]int),
+	}
+}
+
+// Clear removes all agents from the grid.
+func (g *Grid) Clear() {
+	for i := range g.cells {
+		g.cells[i] = g.cells[i][:0]
+	}
+	for id := range g.cellOf {
+		delete(g.cellOf, id)
+	}
+	g.maxExtent = 0
+}
+
+// Insert adds an agent to the grid, in the cell of its current position.
+func (g *Grid) Insert(p physics.Physic) {
+	col, row := g.cell(p.Position().X, p.Position().Y)
+	i := row*g.cols + col
+	g.cells[i] = append(g.cells[i], p)
+	g.cellOf[p.ID()] = i
+	if e := extent(p); e > g.maxExtent {
+		g.maxExtent = e
+	}
+}
+
+// RemoveID deletes an agent from the grid.
+func (g *Grid) RemoveID(id string) {
+	i, ok := g.cellOf[id]
+	if !ok {
+		return
+	}
+	delete(g.cellOf, id)
+	cell := g.cells[i]
+	for j := range cell {
+		if cell[j].ID() == id {
+			g.cells[i] = append(cell[:j], cell[j+1:]...)
+			return
+		}
+	}
+}
+
+// Contains returns true if an agent is in the grid.
+func (g *Grid) Contains(id string) bool {
+	_, ok := g.cellOf[id]
+	return ok
+}
+
+// Len returns the number of agents in the grid.
+func (g *Grid) Len() int {
+	return len(g.cellOf)
+}
+
+// Query returns the agents located strictly less than radius away from (x, y).
+// If agentTypes are given, only agents of these types are returned.
+func (g *Grid) Query(x, y, radius float64, agentTypes ...string) []physics.Physic {
+	result := []physics.Physic{}
+	center := vector.Vector2D{X: x, Y: y}
+	periodX, periodY := g.topology.Period()
+	colSpans := g.spans(x, radius, periodX, g.cols)
+	for _, rows := range g.spans(y, radius, periodY, g.rows) {
+		for row := rows[0]; row <= rows[1]; row++ {
+			for _, cols := range colSpans {
+				for col := cols[0]; col <= cols[1]; col++ {
+					for _, p := range g.cells[row*g.cols+col] {
+						if !isElementOf(p.Type(), agentTypes) {
+							continue
+						}
+						d := g.topology.Delta(center, p.Position())
+						if d.MagnitudeSquared() < radius*radius {
+							result = append(result, p)
+						}
+					}
+				}
+			}
+		}
+	}
+	return result
+}
+
+// Nearby returns the agents which may collide with p, based on their dimension.
+// It is meant to be used as a broad phase before an exact intersection test.
+func (g *Grid) Nearby(p physics.Physic, agentTypes ...string) []physics.Physic {
+	candidates := g.Query(p.Position().X, p.Position().Y, extent(p)+g.maxExtent, agentTypes...)
+	result := candidates[:0]
+	for _, c := range candidates {
+		if c.ID() != p.ID() {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// cell returns the grid cell containing (x, y).
+func (g *Grid) cell(x, y float64) (col, row int) {
+	periodX, periodY := g.topology.Period()
+	x = wrap(x, periodX)
+	y = wrap(y, periodY)
+	col = int(math.Floor(x / g.cellSize))
+	row = int(math.Floor(y / g.cellSize))
+	return clamp(col, g.cols), clamp(row, g.rows)
+}
+
+// spans returns the ranges of cells covered by [v-radius, v+radius] along an axis of n cells.
+// For a non periodic axis, there is a single range. For a periodic axis, the parts of the
+// segment which cross the axis edges are brought back on the other side, in up to three ranges.
+func (g *Grid) spans(v, radius, period float64, n int) [][2]int {
+	shifts := []float64{0}
+	if period > 0 {
+		v = wrap(v, period)
+		shifts = []float64{-period, 0, period}
+	}
+	result := make([][2]int, 0, len(shifts))
+	for _, shift := range shifts {
+		lo, hi := v+shift-radius, v+shift+radius
+		if period > 0 && (hi < 0 || lo >= period) {
+			continue
+		}
+		min := clamp(int(math.Floor(lo/g.cellSize)), n)
+		max := clamp(int(math.Floor(hi/g.cellSize)), n)
+		// merge overlapping ranges, so that a cell is never visited twice
+		if last := len(result) - 1; last >= 0 && min <= result[last][1] {
+			if max > result[last][1] {
+				result[last][1] = max
+			}
+			continue
+		}
+		result = append(result, [2]int{min, max})
+	}
+	return result
+}
+
+// extent returns the distance from an agent's position beyond which its collision shape can not reach.
+func extent(p physics.Physic) float64 {
+	return p.CollisionShape().BoundingRadius()
+}
+
+// wrap brings v back in [0, length), if length is not 0.
+func wrap(v, length float64) float64 {
+	if length <= 0 {
+		return v
+	}
+	v = math.Mod(v, length)
+	if v < 0 {
+		v += length
+	}
+	return v
+}
+
+func clamp(a, n int) int {
+	if a < 0 {
+		return 0
+	}
+	if a >= n {
+		return n - 1
+	}
+	return a
+}
+
+func isElementOf(elt string, arr []string) bool {
+	if len(arr) == 0 {
+		return true
+	}
+	for i := 0; i < len(arr); i++ {
+		if elt == arr[i] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,148 @@
+package spatial_test
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/physics"
+	"github.com/jtbonhomme/asteboids/internal/spatial"
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+func newAgent(x, y float64, agentType string) *physics.Body {
+	b := physics.NewBody(x, y, 10, 10)
+	b.Init(vector.Vector2D{})
+	b.AgentType = agentType
+	return b
+}
+
+func ids(agents []physics.Physic) []string {
+	result := []string{}
+	for _, a := range agents {
+		result = append(result, a.ID())
+	}
+	sort.Strings(result)
+	return result
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestQuery(t *testing.T) {
+	const width, height, radius float64 = 800, 600, 75
+	rng := rand.New(rand.NewSource(1))
+	agents := []physics.Physic{}
+	for i := 0; i < 500; i++ {
+		agents = append(agents, newAgent(rng.Float64()*width, rng.Float64()*height, physics.BoidAgent))
+	}
+
+	for _, wrap := range []bool{false, true} {
+		var topo topology.Topology = topology.Bounded{Width: width, Height: height}
+		if wrap {
+			topo = topology.Toroidal{Width: width, Height: height}
+		}
+		g := spatial.NewGrid(topo, width, height, radius)
+		for _, a := range agents {
+			g.Insert(a)
+		}
+		for i := 0; i < 100; i++ {
+			x, y := rng.Float64()*width, rng.Float64()*height
+			expected := []physics.Physic{}
+			for _, a := range agents {
+				dx, dy := math.Abs(a.Position().X-x), math.Abs(a.Position().Y-y)
+				if wrap {
+					dx = math.Min(dx, width-dx)
+					dy = math.Min(dy, height-dy)
+				}
+				if dx*dx+dy*dy < radius*radius {
+					expected = append(expected, a)
+				}
+			}
+			got := g.Query(x, y, radius)
+			if !equal(ids(expected), ids(got)) {
+				t.Errorf("wrap %t: query (%0.2f, %0.2f) expected %d agents got %d", wrap, x, y, len(expected), len(got))
+			}
+		}
+	}
+}
+
+func TestQueryWrap(t *testing.T) {
+	type TestCase struct {
+		name     string
+		wrap     bool
+		x, y     float64
+		expected int
+	}
+
+	tests := []TestCase{
+		{name: "same side", wrap: false, x: 795, y: 300, expected: 1},
+		{name: "across left edge without wrap", wrap: false, x: 3, y: 300, expected: 0},
+		{name: "across left edge with wrap", wrap: true, x: 3, y: 300, expected: 1},
+		{name: "across top edge with wrap", wrap: true, x: 798, y: 598, expected: 0},
+		{name: "across corner with wrap", wrap: true, x: 2, y: 2, expected: 0},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var topo topology.Topology = topology.Bounded{Width: 800, Height: 600}
+			if tt.wrap {
+				topo = topology.Toroidal{Width: 800, Height: 600}
+			}
+			g := spatial.NewGrid(topo, 800, 600, 50)
+			g.Insert(newAgent(798, 300, physics.BoidAgent))
+			got := g.Query(tt.x, tt.y, 10)
+			if len(got) != tt.expected {
+				t.Errorf("test %s expected %d agents got %d", tt.name, tt.expected, len(got))
+			}
+		})
+	}
+}
+
+func TestQueryTypesAndRemove(t *testing.T) {
+	g := spatial.NewGrid(topology.Toroidal{Width: 800, Height: 600}, 800, 600, 50)
+	boid := newAgent(100, 100, physics.BoidAgent)
+	asteroid := newAgent(110, 100, physics.AsteroidAgent)
+	g.Insert(boid)
+	g.Insert(asteroid)
+
+	if got := g.Query(100, 100, 20, physics.AsteroidAgent); len(got) != 1 || got[0].ID() != asteroid.ID() {
+		t.Errorf("expected only the asteroid got %d agents", len(got))
+	}
+	if got := g.Nearby(boid); len(got) != 1 || got[0].ID() != asteroid.ID() {
+		t.Errorf("expected the asteroid to be nearby got %d agents", len(got))
+	}
+
+	g.RemoveID(asteroid.ID())
+	if g.Len() != 1 {
+		t.Errorf("expected 1 agent after removal got %d", g.Len())
+	}
+	if got := g.Query(100, 100, 20); len(got) != 1 || got[0].ID() != boid.ID() {
+		t.Errorf("expected only the boid got %d agents", len(got))
+	}
+}
+
+func BenchmarkQuery(b *testing.B) {
+	const width, height, radius float64 = 1080, 720, 75
+	rng := rand.New(rand.NewSource(1))
+	g := spatial.NewGrid(topology.Toroidal{Width: width, Height: height}, width, height, radius)
+	for i := 0; i < 2000; i++ {
+		g.Insert(newAgent(rng.Float64()*width, rng.Float64()*height, physics.BoidAgent))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.Query(rng.Float64()*width, rng.Float64()*height, radius)
+	}
+}
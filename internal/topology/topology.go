@@ -0,0 +1,179 @@
+package topology
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+const (
+	ToroidalTopology string = "toroidal"
+	BoundedTopology  string = "bounded"
+	InfiniteTopology string = "infinite"
+)
+
+// Topology describes the shape of the world agents live in.
+// All geometric computations between two agents shall go through a Topology,
+// so that agents behave the same at the screen edges as in the middle of it.
+type Topology interface {
+	// Delta returns the shortest displacement from a to b.
+	Delta(a, b vector.Vector2D) vector.Vector2D
+	// Distance returns the shortest distance between a and b.
+	Distance(a, b vector.Vector2D) float64
+	// Constrain keeps a moving body inside the world.
+	// It returns the corrected position and velocity of the body.
+	Constrain(position, velocity vector.Vector2D) (vector.Vector2D, vector.Vector2D)
+	// Period returns the length after which the world repeats itself along each axis,
+	// or 0 for an axis which does not repeat.
+	Period() (float64, float64)
+}
+
+// New creates a topology from its name, for a width x height world.
+func New(name string, width, height float64) (Topology, error) {
+	switch name {
+	case ToroidalTopology, "":
+		return Toroidal{Width: width, Height: height}, nil
+	case BoundedTopology:
+		return Bounded{Width: width, Height: height}, nil
+	case InfiniteTopology:
+		return Infinite{}, nil
+	default:
+		return nil, fmt.Errorf("unknown topology %s", name)
+	}
+}
+
+// Neighbours returns the indexes of the positions located strictly less than radius away from center.
+func Neighbours(t Topology, center vector.Vector2D, radius float64, positions []vector.Vector2D) []int {
+	result := []int{}
+	for i, p := range positions {
+		d := t.Delta(center, p)
+		if d.MagnitudeSquared() < radius*radius {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// Toroidal is a world whose opposite edges are connected:
+// an agent leaving the screen on one side comes back on the other side.
+type Toroidal struct {
+	Width  float64
+	Height float64
+}
+
+// Delta returns the shortest displacement from a to b, possibly across the edges.
+func (t Toroidal) Delta(a, b vector.Vector2D) vector.Vector2D {
+	return vector.Vector2D{
+		X: wrapDelta(b.X-a.X, t.Width),
+		Y: wrapDelta(b.Y-a.Y, t.Height),
+	}
+}
+
+// Distance returns the shortest distance between a and b, possibly across the edges.
+func (t Toroidal) Distance(a, b vector.Vector2D) float64 {
+	d := t.Delta(a, b)
+	return math.Sqrt(d.MagnitudeSquared())
+}
+
+// Constrain moves a body which left the screen to the opposite edge.
+func (t Toroidal) Constrain(position, velocity vector.Vector2D) (vector.Vector2D, vector.Vector2D) {
+	if position.X > t.Width {
+		position.X = 0
+	} else if position.X < 0 {
+		position.X = t.Width
+	}
+	if position.Y > t.Height {
+		position.Y = 0
+	} else if position.Y < 0 {
+		position.Y = t.Height
+	}
+	return position, velocity
+}
+
+// Period returns the world width and height.
+func (t Toroidal) Period() (float64, float64) {
+	return t.Width, t.Height
+}
+
+// Bounded is a world surrounded by walls: agents bounce on the screen edges.
+type Bounded struct {
+	Width  float64
+	Height float64
+}
+
+// Delta returns the displacement from a to b.
+func (t Bounded) Delta(a, b vector.Vector2D) vector.Vector2D {
+	return vector.Vector2D{
+		X: b.X - a.X,
+		Y: b.Y - a.Y,
+	}
+}
+
+// Distance returns the distance between a and b.
+func (t Bounded) Distance(a, b vector.Vector2D) float64 {
+	return a.Distance(b)
+}
+
+// Constrain reflects a body which crossed a wall, and reverts its velocity.
+func (t Bounded) Constrain(position, velocity vector.Vector2D) (vector.Vector2D, vector.Vector2D) {
+	position.X, velocity.X = reflect(position.X, velocity.X, t.Width)
+	position.Y, velocity.Y = reflect(position.Y, velocity.Y, t.Height)
+	return position, velocity
+}
+
+// Period returns 0, a bounded world does not repeat itself.
+func (t Bounded) Period() (float64, float64) {
+	return 0, 0
+}
+
+// Infinite is a world without edges: agents leaving the screen are lost in space.
+type Infinite struct{}
+
+// Delta returns the displacement from a to b.
+func (t Infinite) Delta(a, b vector.Vector2D) vector.Vector2D {
+	return vector.Vector2D{
+		X: b.X - a.X,
+		Y: b.Y - a.Y,
+	}
+}
+
+// Distance returns the distance between a and b.
+func (t Infinite) Distance(a, b vector.Vector2D) float64 {
+	return a.Distance(b)
+}
+
+// Constrain does not change the body position and velocity.
+func (t Infinite) Constrain(position, velocity vector.Vector2D) (vector.Vector2D, vector.Vector2D) {
+	return position, velocity
+}
+
+// Period returns 0, an infinite world does not repeat itself.
+func (t Infinite) Period() (float64, float64) {
+	return 0, 0
+}
+
+// wrapDelta returns the shortest displacement along a wrapping axis of a given length.
+func wrapDelta(d, length float64) float64 {
+	if length <= 0 {
+		return d
+	}
+	d = math.Mod(d, length)
+	if d > length/2 {
+		d -= length
+	} else if d < -length/2 {
+		d += length
+	}
+	return d
+}
+
+// reflect bounces a coordinate moving at speed v on the 0 and length walls.
+func reflect(x, v, length float64) (float64, float64) {
+	if x < 0 {
+		return math.Min(-x, length), math.Abs(v)
+	}
+	if x > length {
+		return math.Max(2*length-x, 0), -math.Abs(v)
+	}
+	return x, v
+}
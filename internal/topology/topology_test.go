@@ -0,0 +1,71 @@
+package topology_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/jtbonhomme/asteboids/internal/topology"
+	"github.com/jtbonhomme/asteboids/internal/vector"
+)
+
+const epsilon float64 = 1e-9
+
+func TestDelta(t *testing.T) {
+	type TestCase struct {
+		name     string
+		topology topology.Topology
+		a, b     vector.Vector2D
+		expected vector.Vector2D
+	}
+
+	toroidal := topology.Toroidal{Width: 800, Height: 600}
+	bounded := topology.Bounded{Width: 800, Height: 600}
+	tests := []TestCase{
+		{"toroidal middle", toroidal, vector.Vector2D{X: 100, Y: 100}, vector.Vector2D{X: 150, Y: 80}, vector.Vector2D{X: 50, Y: -20}},
+		{"toroidal across left edge", toroidal, vector.Vector2D{X: 2, Y: 300}, vector.Vector2D{X: 797, Y: 300}, vector.Vector2D{X: -5, Y: 0}},
+		{"toroidal across corner", toroidal, vector.Vector2D{X: 798, Y: 598}, vector.Vector2D{X: 3, Y: 1}, vector.Vector2D{X: 5, Y: 3}},
+		{"bounded across left edge", bounded, vector.Vector2D{X: 2, Y: 300}, vector.Vector2D{X: 797, Y: 300}, vector.Vector2D{X: 795, Y: 0}},
+		{"infinite", topology.Infinite{}, vector.Vector2D{X: -1000, Y: 0}, vector.Vector2D{X: 1000, Y: 0}, vector.Vector2D{X: 2000, Y: 0}},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := tt.topology.Delta(tt.a, tt.b)
+			if math.Abs(got.X-tt.expected.X) > epsilon || math.Abs(got.Y-tt.expected.Y) > epsilon {
+				t.Errorf("test %s expected %v got %v", tt.name, tt.expected, got)
+			}
+			d := tt.topology.Distance(tt.a, tt.b)
+			if math.Abs(d-math.Hypot(tt.expected.X, tt.expected.Y)) > epsilon {
+				t.Errorf("test %s expected distance %0.2f got %0.2f", tt.name, math.Hypot(tt.expected.X, tt.expected.Y), d)
+			}
+		})
+	}
+}
+
+func TestConstrain(t *testing.T) {
+	toroidal := topology.Toroidal{Width: 800, Height: 600}
+	p, v := toroidal.Constrain(vector.Vector2D{X: 801, Y: -1}, vector.Vector2D{X: 1, Y: -1})
+	if p.X != 0 || p.Y != 600 || v.X != 1 || v.Y != -1 {
+		t.Errorf("toroidal expected position {0 600} velocity {1 -1} got %v %v", p, v)
+	}
+
+	bounded := topology.Bounded{Width: 800, Height: 600}
+	p, v = bounded.Constrain(vector.Vector2D{X: 803, Y: -2}, vector.Vector2D{X: 3, Y: -2})
+	if p.X != 797 || p.Y != 2 || v.X != -3 || v.Y != 2 {
+		t.Errorf("bounded expected position {797 2} velocity {-3 2} got %v %v", p, v)
+	}
+}
+
+func TestNeighbours(t *testing.T) {
+	toroidal := topology.Toroidal{Width: 800, Height: 600}
+	positions := []vector.Vector2D{
+		{X: 795, Y: 300},
+		{X: 400, Y: 300},
+		{X: 10, Y: 305},
+	}
+	got := topology.Neighbours(toroidal, vector.Vector2D{X: 2, Y: 300}, 20, positions)
+	if len(got) != 2 || got[0] != 0 || got[1] != 2 {
+		t.Errorf("expected neighbours [0 2] got %v", got)
+	}
+}
@@ -68,7 +68,8 @@ func (v Vector2D) Theta() float64 {
 }
 
 func (v Vector2D) Distance(v2 Vector2D) float64 {
-	return math.Sqrt(math.Pow(v2.X-v.X, 2) + math.Pow(v2.Y-v.Y, 2))
+	dx, dy := v2.X-v.X, v2.Y-v.Y
+	return math.Sqrt(dx*dx + dy*dy)
 }
 
 func (v Vector2D) IsNil() bool {
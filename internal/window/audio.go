@@ -0,0 +1,81 @@
+package window
+
+import (
+	"bytes"
+
+	"github.com/hajimehoshi/ebiten/v2/audio"
+	"github.com/jtbonhomme/asteboids/internal/sounds"
+)
+
+const (
+	sampleRate = 11025
+)
+
+// Audio plays the game sounds with ebiten audio players.
+type Audio struct {
+	players map[sounds.Sound]*audio.Player
+	loops   map[sounds.Sound]*audio.Player
+}
+
+// NewAudio creates one audio player per game sound, and per sound played in a loop.
+func NewAudio() *Audio {
+	audioContext := audio.NewContext(sampleRate)
+	a := &Audio{
+		players: make(map[sounds.Sound]*audio.Player),
+		loops:   make(map[sounds.Sound]*audio.Player),
+	}
+	for _, s := range sounds.Sounds {
+		a.players[s] = audio.NewPlayerFromBytes(audioContext, sounds.WAV(s))
+	}
+	for _, s := range sounds.Loops {
+		wav := sounds.WAV(s)
+		// loop length must be a whole number of 16 bits stereo samples
+		loop := audio.NewInfiniteLoop(bytes.NewReader(wav), int64(len(wav)/4*4))
+		p, err := audio.NewPlayer(audioContext, loop)
+		if err != nil {
+			continue
+		}
+		a.loops[s] = p
+	}
+	return a
+}
+
+// Play rewinds and plays a sound.
+func (a *Audio) Play(s sounds.Sound) {
+	p, ok := a.players[s]
+	if !ok {
+		return
+	}
+	go func() {
+		_ = p.Rewind()
+		p.Play()
+	}()
+}
+
+// Loop plays a sound in a loop, until it is stopped.
+func (a *Audio) Loop(s sounds.Sound) {
+	p, ok := a.loops[s]
+	if !ok || p.IsPlaying() {
+		return
+	}
+	p.Play()
+}
+
+// Stop stops a sound played in a loop.
+func (a *Audio) Stop(s sounds.Sound) {
+	p, ok := a.loops[s]
+	if !ok {
+		return
+	}
+	p.Pause()
+}
+
+// SetVolume sets the volume of all sounds.
+func (a *Audio) SetVolume(v float64) {
+	for _, p := range a.players {
+		p.SetVolume(v)
+	}
+	for _, p := range a.loops {
+		p.SetVolume(v)
+	}
+}
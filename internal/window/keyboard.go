@@ -0,0 +1,34 @@
+package window
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jtbonhomme/asteboids/internal/input"
+)
+
+var keys = map[input.Key]ebiten.Key{
+	input.KeyUp:     ebiten.KeyUp,
+	input.KeyLeft:   ebiten.KeyLeft,
+	input.KeyRight:  ebiten.KeyRight,
+	input.KeySpace:  ebiten.KeySpace,
+	input.KeyEscape: ebiten.KeyEscape,
+	input.KeyEnter:  ebiten.KeyEnter,
+	input.KeyD:      ebiten.KeyD,
+	input.KeyM:      ebiten.KeyM,
+	input.KeyTab:    ebiten.KeyTab,
+	input.KeyPlus:   ebiten.KeyEqual,
+	input.KeyMinus:  ebiten.KeyMinus,
+	input.KeyDown:   ebiten.KeyDown,
+	input.KeyH:      ebiten.KeyH,
+}
+
+// Keyboard reads the player's commands from the keyboard.
+type Keyboard struct{}
+
+// IsKeyPressed returns true if key is currently pressed.
+func (Keyboard) IsKeyPressed(key input.Key) bool {
+	k, ok := keys[key]
+	if !ok {
+		return false
+	}
+	return ebiten.IsKeyPressed(k)
+}
@@ -0,0 +1,80 @@
+package window
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/hajimehoshi/ebiten/v2/ebitenutil"
+	"github.com/hajimehoshi/ebiten/v2/text"
+	"github.com/jtbonhomme/asteboids/internal/render"
+	"golang.org/x/image/font"
+)
+
+// Image wraps an ebiten image so that it can be drawn on a Screen.
+type Image struct {
+	*ebiten.Image
+}
+
+// Screen draws on an ebiten image.
+type Screen struct {
+	image *ebiten.Image
+}
+
+// Fill fills the whole screen with a color.
+func (s Screen) Fill(clr color.Color) {
+	s.image.Fill(clr)
+}
+
+// DrawImage draws an image on the screen, transformed by a geometry matrix.
+func (s Screen) DrawImage(img render.Image, geoM render.GeoM) {
+	i, ok := img.(Image)
+	if !ok || i.Image == nil {
+		return
+	}
+	op := &ebiten.DrawImageOptions{}
+	for row := 0; row < 2; row++ {
+		for col := 0; col < 3; col++ {
+			op.GeoM.SetElement(row, col, geoM.Element(row, col))
+		}
+	}
+	s.image.DrawImage(i.Image, op)
+}
+
+// DrawLine draws a line segment between (x1, y1) and (x2, y2).
+func (s Screen) DrawLine(x1, y1, x2, y2 float64, clr color.Color) {
+	ebitenutil.DrawLine(s.image, x1, y1, x2, y2, clr)
+}
+
+// DrawText draws a text, (x, y) being the position of the text baseline.
+func (s Screen) DrawText(msg string, face font.Face, x, y int, clr color.Color) {
+	text.Draw(s.image, msg, face, x, y, clr)
+}
+
+// BoundString returns the bounding box of a text, as it would be drawn by DrawText.
+func (s Screen) BoundString(face font.Face, msg string) image.Rectangle {
+	return text.BoundString(face, msg)
+}
+
+// DebugPrint draws a debug message in the top left corner.
+func (s Screen) DebugPrint(msg string) {
+	ebitenutil.DebugPrint(s.image, msg)
+}
+
+// Renderer creates ebiten images.
+type Renderer struct{}
+
+// NewImage creates an ebiten image from a decoded picture.
+func (Renderer) NewImage(img image.Image) render.Image {
+	return Image{ebiten.NewImageFromImage(img)}
+}
+
+// CurrentTPS returns the current number of ticks per second.
+func (Renderer) CurrentTPS() float64 {
+	return ebiten.CurrentTPS()
+}
+
+// CurrentFPS returns the current number of frames per second.
+func (Renderer) CurrentFPS() float64 {
+	return ebiten.CurrentFPS()
+}
@@ -0,0 +1,31 @@
+package window
+
+import (
+	"github.com/hajimehoshi/ebiten/v2"
+	"github.com/jtbonhomme/asteboids/internal/render"
+)
+
+// Game is a game which can be run in a window.
+type Game interface {
+	// Update proceeds the game state.
+	Update() error
+	// Draw draws the game screen.
+	Draw(render.Screen)
+	// Layout returns the (logical) screen size.
+	Layout(outsideWidth, outsideHeight int) (int, int)
+}
+
+// game adapts a Game to ebiten.
+type game struct {
+	Game
+}
+
+// Draw draws the game screen.
+func (g game) Draw(screen *ebiten.Image) {
+	g.Game.Draw(Screen{image: screen})
+}
+
+// Run opens a window and starts the game loop.
+func Run(g Game) error {
+	return ebiten.RunGame(game{g})
+}